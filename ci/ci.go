@@ -30,6 +30,7 @@ import (
 	"github.com/isangeles/burn/ash"
 
 	"github.com/isangeles/mural/hud"
+	"github.com/isangeles/mural/input"
 	"github.com/isangeles/mural/log"
 	"github.com/isangeles/mural/mainmenu"
 )
@@ -55,6 +56,14 @@ func init() {
 	burn.AddToolHandler(GUISet, guiset)
 	burn.AddToolHandler(GUIExport, guiexport)
 	burn.AddToolHandler(GUIImport, guiimport)
+	input.AddTool(GUIAudio, "play", "stop", "next", "prev", "music-volume",
+		"set-music-volume", "set-music-mute", "effects-volume",
+		"set-effects-volume", "set-effects-mute")
+	input.AddTool(GUIShow, "version", "playable-chars", "net-stats")
+	input.AddTool(GUISet, "resolution", "fow", "time-scale", "exit",
+		"companion")
+	input.AddTool(GUIExport, "avatar", "hud", "hud-state", "chat")
+	input.AddTool(GUIImport, "hud", "hud-state")
 }
 
 // SetMainMenu sets specified main menu as main
@@ -31,6 +31,8 @@ import (
 
 	"github.com/isangeles/mural/config"
 	"github.com/isangeles/mural/data"
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/hud"
 )
 
 // guiexport handles guiexport command.
@@ -76,6 +78,23 @@ func guiexport(cmd burn.Command) (int, string) {
 				GUIExport, err)
 		}
 		return 0, ""
+	case "chat":
+		if guiHUD == nil {
+			return 3, fmt.Sprintf("%s: no HUD set", GUIExport)
+		}
+		format := hud.TranscriptText
+		channels := make([]game.ChatChannel, 0)
+		if len(cmd.Args()) > 0 {
+			format = cmd.Args()[0]
+			for _, a := range cmd.Args()[1:] {
+				channels = append(channels, game.ChatChannel(a))
+			}
+		}
+		path, err := guiHUD.Chat().Export(format, channels...)
+		if err != nil {
+			return 3, fmt.Sprintf("%s: unable to export chat: %v", GUIExport, err)
+		}
+		return 0, path
 	default:
 		return 2, fmt.Sprintf("%s: invalid option: '%s'", GUIExport,
 			cmd.OptionArgs()[0])
@@ -1,7 +1,7 @@
 /*
  * guiset.go
  *
- * Copyright 2019-2024 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -33,6 +33,7 @@ import (
 	"github.com/isangeles/burn"
 	
 	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/game"
 )
 
 // guiset handles guiset command.
@@ -67,6 +68,48 @@ func guiset(cmd burn.Command) (int, string) {
 		fow := cmd.Args()[0] == "on"
 		config.MapFOW = fow
 		return 0, ""
+	case "time-scale":
+		if len(cmd.Args()) < 1 {
+			return 3, fmt.Sprintf("%s: no enought args for: %s", GUISet,
+				cmd.OptionArgs()[0])
+		}
+		if guiHUD == nil || guiHUD.Game() == nil {
+			return 3, fmt.Sprintf("%s: no game set", GUISet)
+		}
+		scale, err := strconv.ParseFloat(cmd.Args()[0], 64)
+		if err != nil {
+			return 3, fmt.Sprintf("%s: invalid input: '%s'", GUISet,
+				cmd.Args()[0])
+		}
+		err = guiHUD.Game().SetTimeScale(scale)
+		if err != nil {
+			return 3, fmt.Sprintf("%s: unable to set time scale: %v", GUISet,
+				err)
+		}
+		return 0, ""
+	case "companion":
+		if len(cmd.Args()) < 2 {
+			return 3, fmt.Sprintf("%s: no enought args for: %s", GUISet,
+				cmd.OptionArgs()[0])
+		}
+		if guiHUD == nil || guiHUD.Game() == nil {
+			return 3, fmt.Sprintf("%s: no game set", GUISet)
+		}
+		char := guiHUD.Game().Char(cmd.Args()[0], cmd.Args()[1])
+		if char == nil {
+			return 3, fmt.Sprintf("%s: character not found: %s %s", GUISet,
+				cmd.Args()[0], cmd.Args()[1])
+		}
+		stance := game.StanceDefensive
+		if len(cmd.Args()) > 2 {
+			stance = game.Stance(cmd.Args()[2])
+		}
+		err := guiHUD.Game().AddCompanion(char, stance)
+		if err != nil {
+			return 3, fmt.Sprintf("%s: unable to add companion: %v", GUISet,
+				err)
+		}
+		return 0, ""
 	case "exit":
 		if guiHUD != nil {
 			guiHUD.Exit()
@@ -30,6 +30,7 @@ import (
 	"github.com/isangeles/burn"
 	
 	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/game"
 )
 
 // guishow handles guishow command.
@@ -50,10 +51,26 @@ func guishow(cmd burn.Command) (int, string) {
 		}
 		out = strings.TrimSpace(out)
 		return 0, out
+	case "net-stats":
+		server := guiServer()
+		if server == nil {
+			return 3, fmt.Sprintf("%s: no game server connection", GUIShow)
+		}
+		return 0, server.Stats().String()
 	default:
 		return 2, fmt.Sprintf("%s: invalid option: '%s'", GUIShow,
 			cmd.OptionArgs()[0])
 	}
 }
 
-
+// guiServer returns current game server connection, or nil
+// if there is no connection.
+func guiServer() *game.Server {
+	if guiHUD != nil && guiHUD.Game() != nil && guiHUD.Game().Server() != nil {
+		return guiHUD.Game().Server()
+	}
+	if guiMenu != nil {
+		return guiMenu.Server()
+	}
+	return nil
+}
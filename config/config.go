@@ -39,36 +39,44 @@ import (
 )
 
 const (
-	Name, Version = "Mural", "0.1.0-dev"
-	ConfFileName  = ".mural"
+	Name, Version   = "Mural", "0.1.0-dev"
+	ConfFileName    = ".mural"
+	HistoryFileName = ".mural-history"
 )
 
 var (
-	Lang             = "english"
-	Module           = ""
-	ModulesPath      = "data/modules"
-	GUIPath          = ""
-	DefaultHUD       = "default.json"
-	Debug            = true
-	Fullscreen       = false
-	MapFOW           = true
-	MapFull          = true
-	Resolution       pixel.Vec
-	MaxFPS           = 60
-	MainFont         = ""
-	MenuMusic        = ""
-	ButtonClickSound = ""
-	EffectsVolume    = 0.0
-	EffectsMute      = false
-	MusicVolume      = 0.0
-	MusicMute        = false
-	LootDespawnTime  = int64(5000)
-	ServerLogin      = ""
-	ServerPassword   = ""
-	ServerHost       = ""
-	ServerPort       = ""
-	ServerTLS        = false
-	ServerClose      = false
+	Lang              = "english"
+	Module            = ""
+	ModulesPath       = "data/modules"
+	GUIPath           = ""
+	DefaultHUD        = "default.json"
+	Debug             = true
+	Fullscreen        = false
+	MapFOW            = true
+	MapFull           = true
+	Resolution        pixel.Vec
+	MaxFPS            = 60
+	MainFont          = ""
+	MenuMusic         = ""
+	ButtonClickSound  = ""
+	EffectsVolume     = 0.0
+	EffectsMute       = false
+	MusicVolume       = 0.0
+	MusicMute         = false
+	LootDespawnTime   = int64(5000)
+	ServerLogin       = ""
+	ServerPassword    = ""
+	ServerHost        = ""
+	ServerPort        = ""
+	ServerTLS         = false
+	ServerClose       = false
+	ServerRecord      = ""
+	ServerReplay      = ""
+	ServerReplaySpeed = 1.0
+	ServerStatsLog    = 0
+	HistorySize       = 100
+	ChatTranscript    []string
+	TranscriptFormat  = "txt"
 )
 
 // Load loads configuration file.
@@ -158,6 +166,15 @@ func Load() error {
 		ServerLogin = conf["server-user"][0]
 		ServerPassword = conf["server-user"][1]
 	}
+	if len(conf["server-credential"]) > 1 && len(conf["server-credential"][1]) > 0 {
+		pass, err := decryptCredential(conf["server-credential"][1])
+		if err != nil {
+			log.Err.Printf("Config: Unable to set server credential: %v", err)
+		} else {
+			ServerLogin = conf["server-credential"][0]
+			ServerPassword = pass
+		}
+	}
 	if len(conf["server"]) > 1 {
 		ServerHost = conf["server"][0]
 		ServerPort = conf["server"][1]
@@ -168,6 +185,37 @@ func Load() error {
 	if len(conf["server-close"]) > 0 {
 		ServerClose = conf["server-close"][0] == "true"
 	}
+	if len(conf["server-record"]) > 0 {
+		ServerRecord = conf["server-record"][0]
+	}
+	if len(conf["server-replay"]) > 0 {
+		ServerReplay = conf["server-replay"][0]
+	}
+	if len(conf["server-replay"]) > 1 {
+		ServerReplaySpeed, err = strconv.ParseFloat(conf["server-replay"][1], 64)
+		if err != nil {
+			log.Err.Printf("Config: Unable to set server replay speed: %v", err)
+		}
+	}
+	if len(conf["server-stats-log"]) > 0 {
+		ServerStatsLog, err = strconv.Atoi(conf["server-stats-log"][0])
+		if err != nil {
+			log.Err.Printf("Config: Unable to set server stats log interval: %v", err)
+		}
+	}
+	if len(conf["history-size"]) > 0 {
+		HistorySize, err = strconv.Atoi(conf["history-size"][0])
+		if err != nil {
+			log.Err.Printf("Config: Unable to set input history size: %v", err)
+		}
+	}
+	if len(conf["chat-transcript"]) > 0 && len(conf["chat-transcript"][0]) > 0 {
+		ChatTranscript = conf["chat-transcript"]
+	}
+	if len(conf["chat-transcript-format"]) > 0 {
+		TranscriptFormat = conf["chat-transcript-format"][0]
+	}
+	loadProfiles(conf)
 	return nil
 }
 
@@ -201,10 +249,26 @@ func Save() error {
 	conf["music-volume"] = []string{fmt.Sprintf("%f", MusicVolume)}
 	conf["music-mute"] = []string{fmt.Sprintf("%v", MusicMute)}
 	conf["loot-despawn-time"] = []string{fmt.Sprintf("%d", LootDespawnTime)}
-	conf["server-user"] = []string{ServerLogin, ServerPassword}
+	conf["server-credential"] = []string{ServerLogin, ""}
+	if len(ServerLogin) > 0 && len(ServerPassword) > 0 {
+		cred, err := encryptCredential(ServerPassword)
+		if err != nil {
+			log.Err.Printf("Config: Unable to save server credential: %v", err)
+		}
+		conf["server-credential"][1] = cred
+	}
 	conf["server"] = []string{ServerHost, ServerPort}
 	conf["server-tls"] = []string{fmt.Sprintf("%v", ServerTLS)}
 	conf["server-close"] = []string{fmt.Sprintf("%v", ServerClose)}
+	conf["server-record"] = []string{ServerRecord}
+	if ServerReplay != "" {
+		conf["server-replay"] = []string{ServerReplay, fmt.Sprintf("%f", ServerReplaySpeed)}
+	}
+	conf["server-stats-log"] = []string{fmt.Sprintf("%d", ServerStatsLog)}
+	conf["history-size"] = []string{fmt.Sprintf("%d", HistorySize)}
+	conf["chat-transcript"] = ChatTranscript
+	conf["chat-transcript-format"] = []string{TranscriptFormat}
+	saveProfiles(conf)
 	confText := text.MarshalConfig(conf)
 	// Write config values
 	writer := bufio.NewWriter(file)
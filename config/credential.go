@@ -0,0 +1,111 @@
+/*
+ * credential.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// Size of the credential key in bytes.
+	credentialKeySize = 32
+)
+
+var (
+	// Path to the file with key for stored server credentials.
+	keyFilePath = ".mural-key"
+)
+
+// encryptCredential encrypts specified server password with
+// the local credential key.
+// Returns encrypted password encoded in base64.
+func encryptCredential(pass string) (string, error) {
+	gcm, err := credentialCipher(true)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", fmt.Errorf("Unable to create nonce: %v", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(pass), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptCredential decrypts specified server password
+// encrypted with the local credential key.
+func decryptCredential(cred string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(cred)
+	if err != nil {
+		return "", fmt.Errorf("Unable to decode credential: %v", err)
+	}
+	gcm, err := credentialCipher(false)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("Invalid credential")
+	}
+	nonce, text := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	pass, err := gcm.Open(nil, nonce, text, nil)
+	if err != nil {
+		return "", fmt.Errorf("Unable to decrypt credential: %v", err)
+	}
+	return string(pass), nil
+}
+
+// credentialCipher returns cipher with the local credential key.
+// If create is true and there is no key file yet, new key is
+// generated and saved in the key file.
+func credentialCipher(create bool) (cipher.AEAD, error) {
+	key, err := os.ReadFile(keyFilePath)
+	if errors.Is(err, os.ErrNotExist) && create {
+		key = make([]byte, credentialKeySize)
+		_, err = io.ReadFull(rand.Reader, key)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to generate key: %v", err)
+		}
+		err = os.WriteFile(keyFilePath, key, 0600)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read key file: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create cipher: %v", err)
+	}
+	return gcm, nil
+}
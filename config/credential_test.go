@@ -0,0 +1,56 @@
+/*
+ * credential_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestCredential tests encrypting and decrypting stored
+// server credential.
+func TestCredential(t *testing.T) {
+	keyFilePath = filepath.Join(t.TempDir(), ".mural-key")
+	// Test.
+	cred, err := encryptCredential("pass")
+	if err != nil {
+		t.Fatalf("Unable to encrypt credential: %v", err)
+	}
+	if cred == "pass" {
+		t.Errorf("Credential not encrypted")
+	}
+	pass, err := decryptCredential(cred)
+	if err != nil {
+		t.Fatalf("Unable to decrypt credential: %v", err)
+	}
+	if pass != "pass" {
+		t.Errorf("Invalid decrypted credential: %s != pass", pass)
+	}
+	// Key from another file.
+	keyFilePath = filepath.Join(t.TempDir(), ".mural-key")
+	_, err = decryptCredential(cred)
+	if err == nil {
+		t.Errorf("No error for decrypting without key")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * profile.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isangeles/mural/log"
+)
+
+const (
+	// Prefix of config keys with server profiles.
+	serverProfilePrefix = "server-profile-"
+)
+
+// Struct for named game server profile.
+type ServerProfile struct {
+	Name string
+	Host string
+	Port string
+	TLS  bool
+}
+
+var (
+	ServerProfiles    []ServerProfile
+	ServerProfileName = ""
+)
+
+// Profile returns server profile with specified name, or nil
+// if there is no such profile.
+func Profile(name string) *ServerProfile {
+	for i := range ServerProfiles {
+		if ServerProfiles[i].Name == name {
+			return &ServerProfiles[i]
+		}
+	}
+	return nil
+}
+
+// SetProfile adds specified server profile, or replaces
+// the existing profile with the same name.
+func SetProfile(profile ServerProfile) error {
+	if len(profile.Name) < 1 || strings.ContainsAny(profile.Name, ";: \n") {
+		return fmt.Errorf("Invalid profile name: '%s'", profile.Name)
+	}
+	if len(profile.Host) < 1 || len(profile.Port) < 1 {
+		return fmt.Errorf("No host or port")
+	}
+	if p := Profile(profile.Name); p != nil {
+		*p = profile
+		if ServerProfileName == profile.Name {
+			SelectProfile(profile.Name)
+		}
+		return nil
+	}
+	ServerProfiles = append(ServerProfiles, profile)
+	sort.Slice(ServerProfiles, func(i, j int) bool {
+		return ServerProfiles[i].Name < ServerProfiles[j].Name
+	})
+	return nil
+}
+
+// RemoveProfile removes server profile with specified name.
+func RemoveProfile(name string) {
+	for i, p := range ServerProfiles {
+		if p.Name == name {
+			ServerProfiles = append(ServerProfiles[:i], ServerProfiles[i+1:]...)
+			break
+		}
+	}
+	if ServerProfileName == name {
+		ServerProfileName = ""
+	}
+}
+
+// SelectProfile sets server profile with specified name as
+// the current server profile.
+// Server host, port and TLS values are set from the profile.
+func SelectProfile(name string) error {
+	p := Profile(name)
+	if p == nil {
+		return fmt.Errorf("Profile not found: %s", name)
+	}
+	ServerProfileName = p.Name
+	ServerHost = p.Host
+	ServerPort = p.Port
+	ServerTLS = p.TLS
+	return nil
+}
+
+// loadProfiles loads server profiles from specified
+// config values.
+func loadProfiles(conf map[string][]string) {
+	ServerProfiles = make([]ServerProfile, 0)
+	for key, values := range conf {
+		if !strings.HasPrefix(key, serverProfilePrefix) || len(values) < 2 {
+			continue
+		}
+		profile := ServerProfile{
+			Name: strings.TrimPrefix(key, serverProfilePrefix),
+			Host: values[0],
+			Port: values[1],
+		}
+		if len(values) > 2 {
+			profile.TLS = values[2] == "true"
+		}
+		err := SetProfile(profile)
+		if err != nil {
+			log.Err.Printf("Config: Unable to set server profile: %v", err)
+		}
+	}
+	if len(conf["server-profile"]) > 0 && len(conf["server-profile"][0]) > 0 {
+		err := SelectProfile(conf["server-profile"][0])
+		if err != nil {
+			log.Err.Printf("Config: Unable to set server profile: %v", err)
+		}
+	}
+}
+
+// saveProfiles adds all server profiles to specified
+// config values.
+func saveProfiles(conf map[string][]string) {
+	conf["server-profile"] = []string{ServerProfileName}
+	for _, p := range ServerProfiles {
+		conf[serverProfilePrefix+p.Name] = []string{p.Host, p.Port,
+			fmt.Sprintf("%v", p.TLS)}
+	}
+}
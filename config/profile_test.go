@@ -0,0 +1,69 @@
+/*
+ * profile_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+// TestProfiles tests saving, loading and selecting
+// server profiles.
+func TestProfiles(t *testing.T) {
+	ServerProfiles = nil
+	err := SetProfile(ServerProfile{Name: "remote", Host: "example.com",
+		Port: "443", TLS: true})
+	if err != nil {
+		t.Fatalf("Unable to set profile: %v", err)
+	}
+	err = SetProfile(ServerProfile{Name: "local", Host: "localhost", Port: "8000"})
+	if err != nil {
+		t.Fatalf("Unable to set profile: %v", err)
+	}
+	err = SetProfile(ServerProfile{Name: "invalid name", Host: "localhost", Port: "8000"})
+	if err == nil {
+		t.Errorf("No error for invalid profile name")
+	}
+	err = SelectProfile("remote")
+	if err != nil {
+		t.Fatalf("Unable to select profile: %v", err)
+	}
+	// Save & load.
+	conf := make(map[string][]string)
+	saveProfiles(conf)
+	ServerProfiles = nil
+	ServerHost, ServerPort, ServerTLS = "", "", false
+	loadProfiles(conf)
+	if len(ServerProfiles) != 2 || ServerProfiles[0].Name != "local" {
+		t.Fatalf("Invalid loaded profiles: %v", ServerProfiles)
+	}
+	if ServerHost != "example.com" || ServerPort != "443" || !ServerTLS {
+		t.Errorf("Invalid server from the selected profile: %s:%s %v",
+			ServerHost, ServerPort, ServerTLS)
+	}
+	// Remove.
+	RemoveProfile("remote")
+	if Profile("remote") != nil || len(ServerProfileName) > 0 {
+		t.Errorf("Profile not removed")
+	}
+}
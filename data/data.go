@@ -109,6 +109,14 @@ func LoadModuleData(path string) (err error) {
 	if err != nil {
 		return fmt.Errorf("unable to import skills graphics: %v", err)
 	}
+	// Merchants pricing, optional.
+	merchantsPath := filepath.Join(path, "merchants")
+	if _, err := os.Stat(merchantsPath); err == nil {
+		res.Merchants, err = ImportMerchantsDir(merchantsPath)
+		if err != nil {
+			return fmt.Errorf("unable to import merchants: %v", err)
+		}
+	}
 	// Translations.
 	translations, err := flamedata.ImportLangDirs(filepath.Join(path, "lang"))
 	if err != nil {
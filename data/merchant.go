@@ -0,0 +1,76 @@
+/*
+ * merchant.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/isangeles/mural/data/res"
+	"github.com/isangeles/mural/log"
+)
+
+// ImportMerchants imports all merchants data from
+// data file with specified path.
+func ImportMerchants(path string) ([]res.MerchantData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open data file: %v", err)
+	}
+	defer file.Close()
+	buf, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read data file: %v", err)
+	}
+	data := new(res.MerchantsData)
+	err = json.Unmarshal(buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON data: %v", err)
+	}
+	return data.Merchants, nil
+}
+
+// ImportMerchantsDir imports all files with merchants data from
+// directory with specified path.
+func ImportMerchantsDir(path string) ([]res.MerchantData, error) {
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read dir: %v", err)
+	}
+	merchants := make([]res.MerchantData, 0)
+	for _, finfo := range files {
+		basePath := filepath.FromSlash(path + "/" + finfo.Name())
+		impMerchants, err := ImportMerchants(basePath)
+		if err != nil {
+			log.Err.Printf("data merchants import: %s: unable to parse file: %v",
+				basePath, err)
+			continue
+		}
+		merchants = append(merchants, impMerchants...)
+	}
+	return merchants, nil
+}
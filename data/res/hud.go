@@ -1,7 +1,7 @@
 /*
  * hud.go
  *
- * Copyright 2018-2024 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2018-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -33,6 +33,8 @@ type HUDData struct {
 	Name    string   `xml:"name,attr" json:"name,attr"`
 	Players []Player `xml:"players>player" json:"players"`
 	Camera  Camera   `xml:"camera" json:"camera"`
+	Party   Party    `xml:"party" json:"party"`
+	Chat    Chat     `xml:"chat" json:"chat"`
 }
 
 // Struct for HUD camera data.
@@ -41,6 +43,39 @@ type Camera struct {
 	Y float64 `xml:"y,attr" json:"y"`
 }
 
+// Struct for HUD player party data.
+type Party struct {
+	Follow     bool          `xml:"follow,attr" json:"follow"`
+	Active     PartyMember   `xml:"active" json:"active"`
+	Selected   []PartyMember `xml:"selected>member" json:"selected"`
+	Companions []Companion   `xml:"companions>companion" json:"companions"`
+}
+
+// Struct for HUD companion data.
+type Companion struct {
+	ID     string `xml:"id,attr" json:"id"`
+	Serial string `xml:"serial,attr" json:"serial"`
+	Stance string `xml:"stance,attr" json:"stance"`
+}
+
+// Struct for HUD chat data.
+type Chat struct {
+	Active string    `xml:"active,attr" json:"active"`
+	Tabs   []ChatTab `xml:"tab" json:"tabs"`
+}
+
+// Struct for HUD chat tab data.
+type ChatTab struct {
+	Name     string   `xml:"name,attr" json:"name"`
+	Channels []string `xml:"channel" json:"channels"`
+}
+
+// Struct for HUD party member data.
+type PartyMember struct {
+	ID     string `xml:"id,attr" json:"id"`
+	Serial string `xml:"serial,attr" json:"serial"`
+}
+
 // Struct for HUD player data (avatar, inventory layout, etc.).
 type Player struct {
 	ID       string `xml:"id" json:"id"`
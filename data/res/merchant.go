@@ -0,0 +1,55 @@
+/*
+ * merchant.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package res
+
+import (
+	"encoding/xml"
+)
+
+// Struct for merchants data.
+type MerchantsData struct {
+	XMLName   xml.Name       `xml:"merchants" json:"-"`
+	Merchants []MerchantData `xml:"merchant" json:"merchants"`
+}
+
+// Struct for merchant pricing data.
+type MerchantData struct {
+	ID string `xml:"id,attr" json:"id"`
+	// Multiplier for prices of items sold by the merchant.
+	BuyMultiplier float64 `xml:"buy-multiplier,attr" json:"buy-multiplier"`
+	// Multiplier for prices of items bought by the merchant.
+	SellMultiplier float64 `xml:"sell-multiplier,attr" json:"sell-multiplier"`
+	// IDs of items that merchant refuses to buy.
+	Refuse    []string           `xml:"refuse>item" json:"refuse"`
+	Discounts []MerchantDiscount `xml:"discounts>discount" json:"discounts"`
+}
+
+// Struct for merchant discount data.
+// Discount is granted for customers with specified flag
+// and attitude of the merchant toward the customer.
+type MerchantDiscount struct {
+	Attitude string  `xml:"attitude,attr" json:"attitude"`
+	Flag     string  `xml:"flag,attr" json:"flag"`
+	Discount float64 `xml:"discount,attr" json:"discount"`
+}
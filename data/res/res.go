@@ -34,6 +34,7 @@ var (
 	Items            []ItemGraphicData
 	Effects          []EffectGraphicData
 	Skills           []SkillGraphicData
+	Merchants        []MerchantData
 	TranslationBases []*flameres.TranslationBaseData
 )
 
@@ -81,6 +82,17 @@ func Skill(id string) *SkillGraphicData {
 	return nil
 }
 
+// Merchant returns pricing data for merchant
+// with specified ID.
+func Merchant(id string) *MerchantData {
+	for _, d := range Merchants {
+		if d.ID == id {
+			return &d
+		}
+	}
+	return nil
+}
+
 // AddTranslationBases adds all translation bases
 // to the translation resources.
 func AddTranslationBases(bases []flameres.TranslationBaseData) {
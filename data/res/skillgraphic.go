@@ -38,4 +38,5 @@ type SkillGraphicData struct {
 	Icon            string `xml:"icon,attr" json:"icon"`
 	ActivationAudio string `xml:"activation-audio,attr" json:"activation-audio"`
 	ActivationAnim  string `xml:"activation-anim,attr" json:"actvation-anim"`
+	Heal            bool   `xml:"heal,attr" json:"heal"`
 }
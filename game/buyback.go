@@ -0,0 +1,99 @@
+/*
+ * buyback.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"github.com/isangeles/flame/item"
+)
+
+const (
+	// Max number of items on merchant buyback list.
+	buybackSize = 12
+)
+
+// Struct for item sold to the merchant, that could be
+// bought back for the price it was sold.
+type BuybackItem struct {
+	Item  item.Item
+	Price int
+}
+
+// Buyback returns items sold to specified merchant during
+// the current game session, that are still in the merchant
+// inventory.
+// The most recently sold items are first.
+func (g *Game) Buyback(merchant item.Container) []BuybackItem {
+	g.buybackMutex.Lock()
+	defer g.buybackMutex.Unlock()
+	items := make([]BuybackItem, 0)
+	list := g.buyback[merchant.ID()+merchant.Serial()]
+	for i := len(list) - 1; i >= 0; i-- {
+		it := list[i].Item
+		if merchant.Inventory().Item(it.ID(), it.Serial()) != nil {
+			items = append(items, list[i])
+		}
+	}
+	return items
+}
+
+// addBuyback adds specified items to the merchant buyback
+// list.
+// The oldest items are removed from the list if the list
+// is full.
+func (g *Game) addBuyback(merchant item.Container, items ...BuybackItem) {
+	g.buybackMutex.Lock()
+	defer g.buybackMutex.Unlock()
+	key := merchant.ID() + merchant.Serial()
+	list := append(g.buyback[key], items...)
+	if len(list) > buybackSize {
+		list = list[len(list)-buybackSize:]
+	}
+	g.buyback[key] = list
+}
+
+// removeBuyback removes specified items from the merchant
+// buyback list.
+func (g *Game) removeBuyback(merchant item.Container, items ...item.Item) {
+	g.buybackMutex.Lock()
+	defer g.buybackMutex.Unlock()
+	key := merchant.ID() + merchant.Serial()
+	list := g.buyback[key][:0]
+	for _, b := range g.buyback[key] {
+		if !containsItem(items, b.Item) {
+			list = append(list, b)
+		}
+	}
+	g.buyback[key] = list
+}
+
+// containsItem checks if specified items contain item with
+// the same ID and serial as specified item.
+func containsItem(items []item.Item, it item.Item) bool {
+	for _, i := range items {
+		if i.ID() == it.ID() && i.Serial() == it.Serial() {
+			return true
+		}
+	}
+	return false
+}
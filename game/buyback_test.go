@@ -0,0 +1,124 @@
+/*
+ * buyback_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	flameres "github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/mural/data/res"
+)
+
+// TestBuyback tests order, size and merchant inventory filter
+// of the merchant buyback list.
+func TestBuyback(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(flameres.ModuleData{}))
+	merchant := NewCharacter(character.New(flameres.CharacterData{ID: "merchant", Level: 1}), game)
+	items := make([]item.Item, buybackSize+2)
+	for i := range items {
+		items[i] = item.NewWeapon(flameres.WeaponData{ID: "weapon"})
+		merchant.Inventory().AddItem(items[i])
+		game.addBuyback(merchant, BuybackItem{items[i], i})
+	}
+	// Test.
+	buyback := game.Buyback(merchant)
+	if len(buyback) != buybackSize {
+		t.Fatalf("Invalid buyback size: %d != %d", len(buyback), buybackSize)
+	}
+	for i, b := range buyback {
+		it := items[len(items)-1-i]
+		if b.Item != it || b.Price != len(items)-1-i {
+			t.Errorf("Invalid buyback item %d: %s#%s %d != %s#%s %d", i,
+				b.Item.ID(), b.Item.Serial(), b.Price, it.ID(), it.Serial(),
+				len(items)-1-i)
+		}
+	}
+	merchant.Inventory().RemoveItem(items[len(items)-1])
+	buyback = game.Buyback(merchant)
+	if len(buyback) != buybackSize-1 {
+		t.Errorf("Invalid buyback size after removing item from inventory: %d != %d",
+			len(buyback), buybackSize-1)
+	}
+	if len(buyback) > 0 && buyback[0].Item != items[len(items)-2] {
+		t.Errorf("Item not in merchant inventory listed in buyback")
+	}
+	game.removeBuyback(merchant, items[len(items)-2])
+	buyback = game.Buyback(merchant)
+	if len(buyback) != buybackSize-2 {
+		t.Errorf("Invalid buyback size after removing item: %d != %d",
+			len(buyback), buybackSize-2)
+	}
+}
+
+// TestBuybackPrice tests buying back items sold to the merchant
+// for the price they were sold.
+func TestBuybackPrice(t *testing.T) {
+	defer func() { res.Merchants = nil }()
+	res.Merchants = []res.MerchantData{{ID: "merchant", BuyMultiplier: 2,
+		SellMultiplier: 0.5}}
+	// Create game.
+	mod := flame.NewModule(flameres.ModuleData{})
+	mod.Chapter().AddAreas(area.New(flameres.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	game := New(mod)
+	merchant := NewCharacter(character.New(flameres.CharacterData{ID: "merchant", Level: 1}), game)
+	customer := NewCharacter(character.New(flameres.CharacterData{ID: "customer", Level: 1}), game)
+	for _, c := range []*Character{merchant, customer} {
+		err := game.SpawnChar(c)
+		if err != nil {
+			t.Fatalf("Unable to spawn character: %v", err)
+		}
+	}
+	it := item.NewWeapon(flameres.WeaponData{ID: "weapon", Value: 100})
+	customer.Inventory().AddItem(it)
+	// Test.
+	game.Trade(merchant, customer, []item.Item{it}, nil)
+	if merchant.Inventory().Item(it.ID(), it.Serial()) == nil {
+		t.Fatalf("Item not sold to the merchant")
+	}
+	buyback := game.Buyback(merchant)
+	if len(buyback) != 1 || buyback[0].Price != 50 {
+		t.Fatalf("Invalid buyback list after trade: %v", buyback)
+	}
+	pricing := game.Pricing(merchant, customer)
+	if price := pricing.BuyPrice(it); price != 50 {
+		t.Errorf("Invalid buyback price: %d != 50", price)
+	}
+	payment := item.NewWeapon(flameres.WeaponData{ID: "payment", Value: 100})
+	customer.Inventory().AddItem(payment)
+	game.Trade(merchant, customer, []item.Item{payment}, []item.Item{it})
+	if customer.Inventory().Item(it.ID(), it.Serial()) == nil {
+		t.Fatalf("Item not bought back from the merchant")
+	}
+	buyback = game.Buyback(merchant)
+	if len(buyback) != 1 || buyback[0].Item != payment {
+		t.Errorf("Invalid buyback list after buying back item: %v", buyback)
+	}
+}
@@ -26,6 +26,9 @@ package game
 import (
 	"errors"
 	"fmt"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/isangeles/flame/character"
 	flameres "github.com/isangeles/flame/data/res"
@@ -40,8 +43,6 @@ import (
 
 	"github.com/isangeles/fire/request"
 
-	"github.com/isangeles/ignite/ai"
-
 	"github.com/isangeles/mural/config"
 	"github.com/isangeles/mural/log"
 )
@@ -49,11 +50,18 @@ import (
 // Struct for game character.
 type Character struct {
 	*character.Character
-	game       *Game
-	name       string
-	combatLog  *objects.Log
-	privateLog *objects.Log
-	onUse      func(o useaction.Usable)
+	game          *Game
+	name          string
+	combatLog     *objects.Log
+	privateLog    *objects.Log
+	onUse         func(o useaction.Usable)
+	moveMutex     sync.Mutex
+	prediction    *movePrediction
+	smoothing     *moveSmoothing
+	interpolation *moveInterpolation
+	waypoints     []Waypoint
+	ordered       bool
+	follow        followState
 }
 
 // NewCharacter creates game wrapper for module character.
@@ -86,10 +94,6 @@ func (c *Character) PrivateLog() *objects.Log {
 // SetOnUseFunc sets function to trigger after using an object.
 func (c *Character) SetOnUseFunc(f func(o useaction.Usable)) {
 	c.onUse = f
-	aiChar := c.aiChar()
-	if aiChar != nil {
-		aiChar.AddOnUseEvent(f)
-	}
 }
 
 // SetPosition sets character position and destination point.
@@ -101,7 +105,7 @@ func (c *Character) SetPosition(x, y float64) {
 	}
 	setPosReq := request.SetPos{c.ID(), c.Serial(), x, y}
 	req := request.Request{SetPos: []request.SetPos{setPosReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send set pos request to the server: %v",
 			c.ID(), c.Serial(), err)
@@ -109,14 +113,25 @@ func (c *Character) SetPosition(x, y float64) {
 }
 
 // SetDestPoint sets destination point for player character.
+// Moving character stops following its current path.
+// If game uses the remote server the move is predicted locally
+// until it's confirmed by the server update.
 func (c *Character) SetDestPoint(x, y float64) {
+	c.clearWaypoints()
+	c.setDestPoint(x, y)
+}
+
+// setDestPoint sets destination point for the character and
+// sends move request to the server, if needed.
+func (c *Character) setDestPoint(x, y float64) {
 	c.Character.SetDestPoint(x, y)
 	if c.game.Server() == nil {
 		return
 	}
+	c.predictMove(x, y, time.Now())
 	moveReq := request.Move{c.ID(), c.Serial(), x, y}
 	req := request.Request{Move: []request.Move{moveReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().QueueReplace("move"+c.ID()+c.Serial(), req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send move request to the server: %v",
 			c.ID(), c.Serial(), err)
@@ -152,7 +167,7 @@ func (c *Character) SetTarget(tar effect.Target) {
 		targetReq.TargetID, targetReq.TargetSerial = tar.ID(), tar.Serial()
 	}
 	req := request.Request{Target: []request.Target{targetReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().QueueReplace("target"+c.ID()+c.Serial(), req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send target request to the server: %v",
 			c.ID(), c.Serial(), err)
@@ -169,9 +184,7 @@ func (c *Character) Train(training *training.TrainerTraining, trainer training.T
 		// If no server then trigger onUse event and return.
 		// With server this event will be triggered after
 		// user response from the server.
-		if c.onUse != nil {
-			c.onUse(training)
-		}
+		c.used(training)
 		return
 	}
 	trainReq := request.Training{
@@ -182,7 +195,7 @@ func (c *Character) Train(training *training.TrainerTraining, trainer training.T
 		UserSerial:    c.Serial(),
 	}
 	req := request.Request{Training: []request.Training{trainReq}}
-	err = c.game.Server().Send(req)
+	err = c.game.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send training request: %v",
 			c.ID(), c.Serial(), err)
@@ -208,9 +221,7 @@ func (c *Character) Use(ob useaction.Usable) {
 		// If no server then trigger onUse event and return.
 		// With server this event will be triggered after
 		// user response from the server.
-		if c.onUse != nil {
-			c.onUse(ob)
-		}
+		c.used(ob)
 		return
 	}
 	useReq := request.Use{
@@ -274,7 +285,7 @@ func (c *Character) Equip(it item.Equiper) error {
 		eqReq.Slots = append(eqReq.Slots, slotReq)
 	}
 	req := request.Request{Equip: []request.Equip{eqReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send equip request: %v",
 			c.ID(), c.Serial(), err)
@@ -295,7 +306,7 @@ func (c *Character) Unequip(it item.Equiper) {
 		ItemSerial: it.Serial(),
 	}
 	req := request.Request{Unequip: []request.Unequip{uneqReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send unequip request: %v",
 			c.ID(), c.Serial(), err)
@@ -306,7 +317,9 @@ func (c *Character) Unequip(it item.Equiper) {
 // and creates loot area object.
 func (c *Character) RemoveItems(items ...item.Item) {
 	reqItems := make(map[string][]string, 0)
+	moves := make([]itemMove, 0)
 	for _, it := range items {
+		moves = append(moves, newItemMove(it, c, nil))
 		c.Inventory().RemoveItem(it)
 		reqItems[it.ID()] = append(reqItems[it.ID()], it.Serial())
 	}
@@ -319,16 +332,18 @@ func (c *Character) RemoveItems(items ...item.Item) {
 		}
 		return
 	}
+	op := c.game.addPendingOp(PendingThrow, nil, moves...)
 	throwItemsReq := request.ThrowItems{
 		ObjectID:     c.ID(),
 		ObjectSerial: c.Serial(),
 		Items:        reqItems,
 	}
 	req := request.Request{ThrowItems: []request.ThrowItems{throwItemsReq}}
-	err := c.game.Server().Send(req)
+	err := c.game.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Character: %s %s: unable to send throw items request: %v",
 			c.ID(), c.Serial(), err)
+		c.game.cancelPendingOp(op)
 	}
 }
 
@@ -381,28 +396,25 @@ func (c *Character) meetTargetRangeReqs(reqs ...req.Requirement) bool {
 // to the specified position.
 func (c *Character) moveCloseTo(x, y, minRange float64) {
 	charX, charY := c.Position()
-	switch {
-	case x > charX:
-		x -= minRange
-	case x < charX:
-		x += minRange
-	case y > charY:
-		y -= minRange
-	case y < charY:
-		y += minRange
+	dist := math.Hypot(x-charX, y-charY)
+	if dist <= minRange {
+		return
 	}
-	c.SetDestPoint(x, y)
+	destX := x - (x-charX)/dist*minRange
+	destY := y - (y-charY)/dist*minRange
+	if c.MoveTo(destX, destY) {
+		return
+	}
+	c.MoveTo(x, y)
 }
 
-// aiChar returns AI wrapper for the character, or nil
-// if AI doesn't control the character.
-func (c *Character) aiChar() *ai.Character {
-	for _, aiChar := range c.game.localAI.Game().Characters() {
-		if c.ID() == aiChar.ID() && c.Serial() == aiChar.Serial() {
-			return aiChar
-		}
+// used triggers onUse function and emits skill used event
+// for specified usable object.
+func (c *Character) used(ob useaction.Usable) {
+	if c.onUse != nil {
+		c.onUse(ob)
 	}
-	return nil
+	c.game.events.Emit(SkillUsedEvent{c, ob})
 }
 
 // spawnLoot creates new loot area object on current
@@ -0,0 +1,147 @@
+/*
+ * chat.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/isangeles/flame/data/res/lang"
+	"github.com/isangeles/flame/objects"
+)
+
+// Type for chat channel.
+type ChatChannel string
+
+const (
+	ChatSay     ChatChannel = "say"
+	ChatParty   ChatChannel = "party"
+	ChatWhisper ChatChannel = "whisper"
+	ChatSystem  ChatChannel = "system"
+	ChatCombat  ChatChannel = "combat"
+	ChatLoot    ChatChannel = "loot"
+)
+
+const (
+	// Prefixes of chat messages for party and whisper
+	// channels.
+	partyChatPrefix   = "/p"
+	whisperChatPrefix = "/w"
+)
+
+// Struct for chat message.
+type ChatMessage struct {
+	Channel ChatChannel
+	// Name or ID of the whisper recipient.
+	Recipient string
+	Text      string
+}
+
+// ChatChannels returns all chat channels.
+func ChatChannels() []ChatChannel {
+	return []ChatChannel{ChatSay, ChatParty, ChatWhisper, ChatSystem,
+		ChatCombat, ChatLoot}
+}
+
+// ParseChat parses specified chat text.
+// Text starting with '/p' is a party message, text starting
+// with '/w [name]' is a whisper to the character with
+// specified name or ID, any other text is a say message.
+func ParseChat(text string) ChatMessage {
+	cmd, args, _ := strings.Cut(text, " ")
+	switch cmd {
+	case partyChatPrefix:
+		return ChatMessage{Channel: ChatParty, Text: args}
+	case whisperChatPrefix:
+		recipient, text, _ := strings.Cut(args, " ")
+		return ChatMessage{Channel: ChatWhisper, Recipient: recipient, Text: text}
+	default:
+		return ChatMessage{Channel: ChatSay, Text: text}
+	}
+}
+
+// String returns chat message text with channel prefix,
+// parsable by the ParseChat function.
+func (cm ChatMessage) String() string {
+	switch cm.Channel {
+	case ChatParty:
+		return partyChatPrefix + " " + cm.Text
+	case ChatWhisper:
+		return whisperChatPrefix + " " + cm.Recipient + " " + cm.Text
+	default:
+		return cm.Text
+	}
+}
+
+// Chat adds specified message to the character chat log.
+// Channel of the message is encoded in the message text, so
+// it's also received by other clients of the game server.
+// Returns error for party and whisper messages in games with
+// the game server, since the server sends chat messages to all
+// clients and these messages would not be private.
+func (c *Character) Chat(message ChatMessage) error {
+	if c.game.Server() != nil &&
+		(message.Channel == ChatParty || message.Channel == ChatWhisper) {
+		return errors.New(lang.Text("chat_private_server_error"))
+	}
+	c.AddChatMessage(objects.NewMessage(message.String(), true))
+	return nil
+}
+
+// ChatVisible checks if specified chat message from character
+// with specified ID and serial should be visible for the player.
+// Party messages are visible only if the author is a member of
+// the player party, whisper messages only if the author or the
+// recipient is a member of the player party.
+func (g *Game) ChatVisible(authorID, authorSerial string, message ChatMessage) bool {
+	switch message.Channel {
+	case ChatParty:
+		return g.playerChar(authorID, authorSerial) != nil
+	case ChatWhisper:
+		if g.playerChar(authorID, authorSerial) != nil {
+			return true
+		}
+		for _, c := range g.PlayerChars() {
+			if strings.EqualFold(c.ID(), message.Recipient) ||
+				strings.EqualFold(c.Name(), message.Recipient) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// playerChar returns player character with specified ID and
+// serial, or nil if there is no such character in the player
+// party.
+func (g *Game) playerChar(id, serial string) *Character {
+	for _, c := range g.PlayerChars() {
+		if c.ID() == id && c.Serial() == serial {
+			return c
+		}
+	}
+	return nil
+}
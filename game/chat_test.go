@@ -0,0 +1,102 @@
+/*
+ * chat_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestParseChat tests parsing chat messages.
+func TestParseChat(t *testing.T) {
+	msg := ParseChat("hello there")
+	if msg.Channel != ChatSay || msg.Text != "hello there" {
+		t.Errorf("Invalid say message: %v", msg)
+	}
+	msg = ParseChat("/p hello party")
+	if msg.Channel != ChatParty || msg.Text != "hello party" {
+		t.Errorf("Invalid party message: %v", msg)
+	}
+	msg = ParseChat("/w bob hello bob")
+	if msg.Channel != ChatWhisper || msg.Recipient != "bob" || msg.Text != "hello bob" {
+		t.Errorf("Invalid whisper message: %v", msg)
+	}
+	msg = ParseChat("/pass")
+	if msg.Channel != ChatSay {
+		t.Errorf("Invalid channel: %s != %s", msg.Channel, ChatSay)
+	}
+	text := "/w bob hello bob"
+	if ParseChat(text).String() != text {
+		t.Errorf("Invalid message text: '%s' != '%s'", ParseChat(text).String(), text)
+	}
+}
+
+// TestGameChatVisible tests visibility of chat messages
+// for the player.
+func TestGameChatVisible(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	pc := NewCharacter(character.New(res.CharacterData{ID: "pc", Level: 1}), game)
+	game.AddPlayerChar(pc)
+	npc := NewCharacter(character.New(res.CharacterData{ID: "npc", Level: 1}), game)
+	// Test.
+	if !game.ChatVisible(npc.ID(), npc.Serial(), ParseChat("hello")) {
+		t.Errorf("Say message not visible")
+	}
+	if game.ChatVisible(npc.ID(), npc.Serial(), ParseChat("/p hello")) {
+		t.Errorf("Party message from outside the party visible")
+	}
+	if !game.ChatVisible(pc.ID(), pc.Serial(), ParseChat("/p hello")) {
+		t.Errorf("Party message not visible")
+	}
+	if game.ChatVisible(npc.ID(), npc.Serial(), ParseChat("/w bob hello")) {
+		t.Errorf("Whisper to other character visible")
+	}
+	if !game.ChatVisible(npc.ID(), npc.Serial(), ParseChat("/w PC hello")) {
+		t.Errorf("Whisper to player character not visible")
+	}
+}
+
+// TestCharacterChatServer tests refusing party and whisper
+// messages in games with the game server.
+func TestCharacterChatServer(t *testing.T) {
+	// Create game.
+	game, _ := newLoopbackGame(t)
+	defer game.Server().Close()
+	char := NewCharacter(character.New(res.CharacterData{ID: "char", Level: 1}), game)
+	// Test.
+	if err := char.Chat(ParseChat("hello")); err != nil {
+		t.Errorf("Say message refused: %v", err)
+	}
+	if err := char.Chat(ParseChat("/p hello")); err == nil {
+		t.Errorf("Party message not refused")
+	}
+	if err := char.Chat(ParseChat("/w bob hello")); err == nil {
+		t.Errorf("Whisper message not refused")
+	}
+}
@@ -0,0 +1,294 @@
+/*
+ * companion.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/effect"
+	"github.com/isangeles/flame/useaction"
+
+	"github.com/isangeles/mural/data/res"
+)
+
+// Type for companion combat stance.
+type Stance string
+
+const (
+	// Passive companion never attacks, only follows the
+	// leader and heals the party.
+	StancePassive Stance = "passive"
+	// Defensive companion attacks only enemies targeting
+	// party members.
+	StanceDefensive Stance = "defensive"
+	// Aggressive companion also assists the leader by
+	// attacking the leader's target.
+	StanceAggressive Stance = "aggressive"
+)
+
+const (
+	// Time in milliseconds between companions updates.
+	companionUpdateTime = 500
+	// Health percentage below which companions heal party
+	// members.
+	companionHealThreshold = 0.5
+)
+
+// Interface for objects with health.
+type healthObject interface {
+	Live() bool
+	Health() int
+	MaxHealth() int
+}
+
+// Stances returns all companion stances.
+func Stances() []Stance {
+	return []Stance{StancePassive, StanceDefensive, StanceAggressive}
+}
+
+// AddCompanion adds specified character to the player party
+// as a companion steered by the local AI, with specified
+// stance.
+// Active player character is not changed.
+func (g *Game) AddCompanion(char *Character, stance Stance) error {
+	if !validStance(stance) {
+		return fmt.Errorf("Invalid stance: %s", stance)
+	}
+	g.chars.addPlayer(char)
+	g.companionsMutex.Lock()
+	defer g.companionsMutex.Unlock()
+	g.companions[char] = stance
+	return nil
+}
+
+// Companion checks if specified character is a companion.
+func (g *Game) Companion(char *Character) bool {
+	g.companionsMutex.RLock()
+	defer g.companionsMutex.RUnlock()
+	_, ok := g.companions[char]
+	return ok
+}
+
+// CompanionStance returns stance of specified companion.
+func (g *Game) CompanionStance(char *Character) Stance {
+	g.companionsMutex.RLock()
+	defer g.companionsMutex.RUnlock()
+	return g.companions[char]
+}
+
+// SetCompanionStance sets stance for specified companion.
+func (g *Game) SetCompanionStance(char *Character, stance Stance) error {
+	if !validStance(stance) {
+		return fmt.Errorf("Invalid stance: %s", stance)
+	}
+	g.companionsMutex.Lock()
+	defer g.companionsMutex.Unlock()
+	if _, ok := g.companions[char]; !ok {
+		return fmt.Errorf("Character is not a companion: %s %s", char.ID(),
+			char.Serial())
+	}
+	g.companions[char] = stance
+	return nil
+}
+
+// updateCompanions steers all companions from the area of the
+// active player character.
+// Companion heals party members with low health, attacks enemies
+// according to its stance and follows the active player
+// character in formation, if follow mode is enabled.
+// The active player character is never steered by the AI, even
+// if it's a companion, companions moved by the player are not
+// steered until they reach the destination.
+func (g *Game) updateCompanions(delta int64) {
+	g.companionTimer += delta
+	if g.companionTimer < companionUpdateTime {
+		return
+	}
+	g.companionTimer = 0
+	leader := g.ActivePlayerChar()
+	if leader == nil {
+		return
+	}
+	leaderArea := g.Chapter().ObjectArea(leader)
+	if leaderArea == nil {
+		return
+	}
+	party := g.PlayerChars()
+	i := 0
+	for _, c := range party {
+		if c == leader {
+			continue
+		}
+		slot := i
+		i++
+		area := g.Chapter().ObjectArea(c)
+		if !g.Companion(c) || !c.Live() || area == nil || area.ID() != leaderArea.ID() {
+			continue
+		}
+		if c.Casted() != nil || c.orderPending() {
+			continue
+		}
+		if c.healParty(party) {
+			continue
+		}
+		if enemy := g.companionEnemy(c, leader, party); enemy != nil {
+			c.attack(enemy)
+			continue
+		}
+		if g.FollowLeader() {
+			c.followLeader(leader, slot)
+		}
+	}
+}
+
+// companionEnemy returns enemy for specified companion to attack,
+// or nil if there is no enemy for the companion stance.
+func (g *Game) companionEnemy(comp, leader *Character, party []*Character) *Character {
+	stance := g.CompanionStance(comp)
+	if stance == StancePassive {
+		return nil
+	}
+	// Assist the leader.
+	if stance == StanceAggressive && len(leader.Targets()) > 0 {
+		tar := leader.Targets()[0]
+		enemy := g.Char(tar.ID(), tar.Serial())
+		if enemy != nil && enemy.Live() && enemy.AttitudeFor(comp) == character.Hostile {
+			return enemy
+		}
+	}
+	// Defend the party.
+	if len(comp.Targets()) > 0 {
+		tar := comp.Targets()[0]
+		enemy := g.Char(tar.ID(), tar.Serial())
+		if enemy != nil && enemy.Live() && enemy.AttitudeFor(comp) == character.Hostile {
+			return enemy
+		}
+	}
+	var enemy *Character
+	g.RangeChars(func(c *Character) bool {
+		if !c.Live() || c.AttitudeFor(comp) != character.Hostile || len(c.Targets()) < 1 {
+			return true
+		}
+		for _, m := range party {
+			if targets(c, m) {
+				enemy = c
+				return false
+			}
+		}
+		return true
+	})
+	return enemy
+}
+
+// healParty uses the first available healing skill on the party
+// member with the lowest health, if the member health is below
+// the heal threshold.
+// Returns true if the healing skill was used.
+func (c *Character) healParty(party []*Character) bool {
+	wounded, ok := mostWounded(party)
+	if !ok {
+		return false
+	}
+	heal := c.readySkill(true)
+	if heal == nil {
+		return false
+	}
+	c.SetTarget(wounded)
+	c.Use(heal)
+	return true
+}
+
+// mostWounded returns the live object with the lowest health
+// percentage, below the heal threshold.
+// Returns false if there is no such object.
+func mostWounded[T healthObject](objects []T) (wounded T, found bool) {
+	for _, o := range objects {
+		if !o.Live() || o.MaxHealth() < 1 {
+			continue
+		}
+		health := float64(o.Health()) / float64(o.MaxHealth())
+		if health >= companionHealThreshold {
+			continue
+		}
+		if !found || o.Health()*wounded.MaxHealth() < wounded.Health()*o.MaxHealth() {
+			wounded, found = o, true
+		}
+	}
+	return
+}
+
+// attack targets specified enemy and uses the first available
+// skill that is not a healing skill.
+func (c *Character) attack(enemy *Character) {
+	if !targets(c, enemy) {
+		c.SetTarget(enemy)
+	}
+	skill := c.readySkill(false)
+	if skill == nil {
+		return
+	}
+	c.Use(skill)
+}
+
+// readySkill returns the first character skill without
+// cooldown, healing or not, as specified.
+// Skills are marked as healing skills in the skill
+// graphic data.
+func (c *Character) readySkill(heal bool) useaction.Usable {
+	if c.Cooldown() > 0 {
+		return nil
+	}
+	for _, s := range c.Skills() {
+		if s.UseAction() == nil || s.UseAction().Cooldown() > 0 {
+			continue
+		}
+		data := res.Skill(s.ID())
+		if (data != nil && data.Heal) == heal {
+			return s
+		}
+	}
+	return nil
+}
+
+// targets checks if specified character targets specified
+// object.
+func targets(c *Character, tar effect.Target) bool {
+	for _, t := range c.Targets() {
+		if t.ID() == tar.ID() && t.Serial() == tar.Serial() {
+			return true
+		}
+	}
+	return false
+}
+
+// validStance checks if specified stance is supported.
+func validStance(stance Stance) bool {
+	for _, s := range Stances() {
+		if s == stance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+ * companion_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestGameCompanion tests adding companions and changing
+// companion stance.
+func TestGameCompanion(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	// Create characters.
+	pc := NewCharacter(character.New(res.CharacterData{ID: "pc", Level: 1}), game)
+	game.AddPlayerChar(pc)
+	comp := NewCharacter(character.New(res.CharacterData{ID: "comp", Level: 1}), game)
+	// Test.
+	err := game.AddCompanion(comp, Stance("invalid"))
+	if err == nil {
+		t.Errorf("No error for invalid stance")
+	}
+	err = game.AddCompanion(comp, StanceDefensive)
+	if err != nil {
+		t.Fatalf("Unable to add companion: %v", err)
+	}
+	if !game.Companion(comp) || game.Companion(pc) {
+		t.Errorf("Invalid companions")
+	}
+	if len(game.PlayerChars()) != 2 {
+		t.Errorf("Companion not added to player characters")
+	}
+	if game.ActivePlayerChar() != pc {
+		t.Errorf("Active player character changed after adding companion")
+	}
+	err = game.SetCompanionStance(comp, StanceAggressive)
+	if err != nil {
+		t.Fatalf("Unable to set companion stance: %v", err)
+	}
+	if game.CompanionStance(comp) != StanceAggressive {
+		t.Errorf("Invalid companion stance: %s != %s", game.CompanionStance(comp),
+			StanceAggressive)
+	}
+	err = game.SetCompanionStance(pc, StancePassive)
+	if err == nil {
+		t.Errorf("No error for setting stance of non-companion")
+	}
+}
+
+// TestCompanionEnemy tests selecting enemies for companions
+// with different stances.
+func TestCompanionEnemy(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	mod.Chapter().AddAreas(area.New(res.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	game := New(mod)
+	// Create characters.
+	leader := NewCharacter(character.New(res.CharacterData{ID: "leader", Level: 1}), game)
+	comp := NewCharacter(character.New(res.CharacterData{ID: "comp", Level: 1}), game)
+	hostileData := res.CharacterData{ID: "enemy", Level: 1, Attitude: string(character.Hostile)}
+	attacker := NewCharacter(character.New(hostileData), game)
+	hostileData.ID = "target"
+	target := NewCharacter(character.New(hostileData), game)
+	for _, c := range []*Character{leader, comp, attacker, target} {
+		err := game.SpawnChar(c)
+		if err != nil {
+			t.Fatalf("Unable to spawn character: %v", err)
+		}
+	}
+	game.AddPlayerChar(leader)
+	err := game.AddCompanion(comp, StancePassive)
+	if err != nil {
+		t.Fatalf("Unable to add companion: %v", err)
+	}
+	party := game.PlayerChars()
+	leader.SetTarget(target)
+	// Test.
+	if enemy := game.companionEnemy(comp, leader, party); enemy != nil {
+		t.Errorf("Enemy for passive companion: %s", enemy.ID())
+	}
+	game.SetCompanionStance(comp, StanceDefensive)
+	if enemy := game.companionEnemy(comp, leader, party); enemy != nil {
+		t.Errorf("Enemy for defensive companion without attackers: %s", enemy.ID())
+	}
+	attacker.SetTarget(leader)
+	if enemy := game.companionEnemy(comp, leader, party); enemy != attacker {
+		t.Errorf("Invalid enemy for defensive companion: %v", enemy)
+	}
+	game.SetCompanionStance(comp, StanceAggressive)
+	if enemy := game.companionEnemy(comp, leader, party); enemy != target {
+		t.Errorf("Invalid enemy for aggressive companion: %v", enemy)
+	}
+}
+
+// Struct for test object with health.
+type testHealthObject struct {
+	health, maxHealth int
+}
+
+// Live checks if test object is alive.
+func (o testHealthObject) Live() bool {
+	return o.health > 0
+}
+
+// Health returns health of the test object.
+func (o testHealthObject) Health() int {
+	return o.health
+}
+
+// MaxHealth returns max health of the test object.
+func (o testHealthObject) MaxHealth() int {
+	return o.maxHealth
+}
+
+// TestMostWounded tests selecting party member to heal.
+func TestMostWounded(t *testing.T) {
+	tests := []struct {
+		objects []testHealthObject
+		wounded int
+	}{
+		{[]testHealthObject{{100, 100}, {60, 100}}, -1},
+		{[]testHealthObject{{100, 100}, {50, 100}}, -1},
+		{[]testHealthObject{{100, 100}, {49, 100}}, 1},
+		{[]testHealthObject{{40, 100}, {10, 50}, {30, 100}}, 1},
+		{[]testHealthObject{{0, 100}, {40, 100}}, 1},
+		{[]testHealthObject{{0, 0}, {0, 100}}, -1},
+	}
+	for i, test := range tests {
+		wounded, ok := mostWounded(test.objects)
+		if test.wounded < 0 {
+			if ok {
+				t.Errorf("Test %d: wounded object for no wounded objects: %v",
+					i, wounded)
+			}
+			continue
+		}
+		if !ok || wounded != test.objects[test.wounded] {
+			t.Errorf("Test %d: invalid wounded object: %v != %v", i, wounded,
+				test.objects[test.wounded])
+		}
+	}
+}
@@ -0,0 +1,191 @@
+/*
+ * event.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/dialog"
+	"github.com/isangeles/flame/item"
+	"github.com/isangeles/flame/useaction"
+)
+
+// Interface for game events.
+type Event interface {
+	event()
+}
+
+// Event emitted after new character appeared in the
+// current chapter.
+// Emitted during the game state update, subscribers must
+// not step the game or wait for the server responses.
+type CharSpawnedEvent struct {
+	Char *Character
+}
+
+// Event emitted after character was removed from the
+// current chapter.
+// Emitted during the game state update, subscribers must
+// not step the game or wait for the server responses.
+type CharDespawnedEvent struct {
+	Char *Character
+}
+
+// Event emitted after active player character change.
+type ActivePlayerCharChangedEvent struct {
+	Char *Character
+}
+
+// Event emitted after items transfer between containers.
+// With the game server the event is emitted after the server
+// confirmed the transfer, rejected transfer emits no event.
+type ItemsTransferredEvent struct {
+	From  item.Container
+	To    item.Container
+	Items []item.Item
+}
+
+// Event emitted after items exchange between seller
+// and buyer.
+// With the game server the event is emitted after the server
+// confirmed the trade, rejected trade emits no event.
+type TradeCompletedEvent struct {
+	Seller    item.Container
+	Buyer     item.Container
+	SellItems []item.Item
+	BuyItems  []item.Item
+}
+
+// Event emitted after dialog start.
+type DialogStartedEvent struct {
+	Dialog *dialog.Dialog
+	Target dialog.Talker
+}
+
+// Event emitted after dialog answer.
+type DialogAnsweredEvent struct {
+	Dialog *dialog.Dialog
+	Answer *dialog.Answer
+}
+
+// Event emitted after dialog end.
+type DialogEndedEvent struct {
+	Dialog *dialog.Dialog
+}
+
+// Event emitted after character used skill, item
+// or other usable object.
+type SkillUsedEvent struct {
+	Char   *Character
+	Object useaction.Usable
+}
+
+// Event emitted after current chapter change.
+type ChapterChangedEvent struct {
+	Chapter *flame.Chapter
+}
+
+// Event emitted after server connection state change.
+type ServerStateChangedEvent struct {
+	State ConnState
+}
+
+func (CharSpawnedEvent) event()             {}
+func (CharDespawnedEvent) event()           {}
+func (ActivePlayerCharChangedEvent) event() {}
+func (ItemsTransferredEvent) event()        {}
+func (TradeCompletedEvent) event()          {}
+func (DialogStartedEvent) event()           {}
+func (DialogAnsweredEvent) event()          {}
+func (DialogEndedEvent) event()             {}
+func (SkillUsedEvent) event()               {}
+func (ChapterChangedEvent) event()          {}
+func (ServerStateChangedEvent) event()      {}
+
+// Struct for game events bus.
+// Bus is safe for concurrent use.
+type EventBus struct {
+	mutex       sync.RWMutex
+	nextID      int64
+	subscribers map[int64]func(e Event)
+}
+
+// NewEventBus creates new event bus.
+func NewEventBus() *EventBus {
+	eb := EventBus{subscribers: make(map[int64]func(e Event))}
+	return &eb
+}
+
+// Subscribe adds specified function as subscriber of all
+// events emitted on the bus.
+// Returns subscription ID that can be used to unsubscribe.
+func (eb *EventBus) Subscribe(f func(e Event)) int64 {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.nextID++
+	eb.subscribers[eb.nextID] = f
+	return eb.nextID
+}
+
+// Unsubscribe removes subscriber with specified subscription ID.
+func (eb *EventBus) Unsubscribe(id int64) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	delete(eb.subscribers, id)
+}
+
+// Emit calls all subscribers with specified event, in order
+// of subscription.
+// Subscribers are called on the snapshot of the subscribers
+// list, so it's safe to subscribe and unsubscribe inside
+// the subscriber function.
+func (eb *EventBus) Emit(e Event) {
+	eb.mutex.RLock()
+	ids := make([]int64, 0, len(eb.subscribers))
+	for id := range eb.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	subs := make([]func(e Event), len(ids))
+	for i, id := range ids {
+		subs[i] = eb.subscribers[id]
+	}
+	eb.mutex.RUnlock()
+	for _, f := range subs {
+		f(e)
+	}
+}
+
+// SubscribeTo adds specified function as subscriber of all
+// events of type E emitted on specified bus.
+// Returns subscription ID that can be used to unsubscribe.
+func SubscribeTo[E Event](eb *EventBus, f func(e E)) int64 {
+	return eb.Subscribe(func(e Event) {
+		if e, ok := e.(E); ok {
+			f(e)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+/*
+ * event_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+)
+
+// TestEventBus tests emitting events to multiple subscribers
+// and unsubscribing.
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	all := 0
+	bus.Subscribe(func(e Event) {
+		all++
+	})
+	states := make([]ConnState, 0)
+	sub := SubscribeTo(bus, func(e ServerStateChangedEvent) {
+		states = append(states, e.State)
+	})
+	bus.Emit(ServerStateChangedEvent{Reconnecting})
+	bus.Emit(ChapterChangedEvent{})
+	bus.Unsubscribe(sub)
+	bus.Emit(ServerStateChangedEvent{Connected})
+	if all != 3 {
+		t.Errorf("Invalid number of events received by the subscriber: %d != 3",
+			all)
+	}
+	if len(states) != 1 || states[0] != Reconnecting {
+		t.Errorf("Invalid events received by the typed subscriber: %v",
+			states)
+	}
+}
+
+// TestEventBusUnsubscribeInside tests unsubscribing from the
+// subscriber function.
+func TestEventBusUnsubscribeInside(t *testing.T) {
+	bus := NewEventBus()
+	calls := 0
+	var sub int64
+	sub = bus.Subscribe(func(e Event) {
+		calls++
+		bus.Unsubscribe(sub)
+	})
+	bus.Emit(ChapterChangedEvent{})
+	bus.Emit(ChapterChangedEvent{})
+	if calls != 1 {
+		t.Errorf("Subscriber called after unsubscribe: %d calls", calls)
+	}
+}
@@ -1,7 +1,7 @@
 /*
  * game.go
  *
- * Copyright 2020-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -25,7 +25,11 @@
 package game
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/isangeles/flame"
@@ -43,83 +47,222 @@ import (
 
 const (
 	aiCharFlag = flag.Flag("igniteNpc")
+	// Game update time step.
+	stepTime = 16 * time.Millisecond
+	// Max number of update steps per one loop iteration.
+	maxSteps = 8
 )
 
 // Wrapper struct for game.
 type Game struct {
 	*flame.Module
-	chars              map[string]*Character
-	playerChars        []*Character
-	activePC           *Character
+	chars              *charRegistry
+	events             *EventBus
 	server             *Server
 	localAI            *ai.AI
+	loopMutex          sync.Mutex
+	loopCond           *sync.Cond
 	closing            bool
 	pause              bool
+	timeScale          float64
+	pathFinder         PathFinder
+	pathMutex          sync.Mutex
+	followLeader       atomic.Bool
+	spectator          atomic.Bool
+	companionsMutex    sync.RWMutex
+	companions         map[*Character]Stance
+	companionTimer     int64
+	buybackMutex       sync.Mutex
+	buyback            map[string][]BuybackItem
 	onPlayerCharChange func(c *Character)
+	onPendingRollback  func(op *PendingOp)
+	onChapterChange    func(c *flame.Chapter)
+	pendingMutex       sync.Mutex
+	pendingOps         []*PendingOp
+	nextPendingID      int64
+	lastUpdate         time.Time
 }
 
 // New creates new wrapper for specified module.
 func New(module *flame.Module) *Game {
 	g := Game{
-		Module: module,
-		chars:  make(map[string]*Character),
-	}
+		Module:     module,
+		chars:      newCharRegistry(),
+		events:     NewEventBus(),
+		companions: make(map[*Character]Stance),
+		buyback:    make(map[string][]BuybackItem),
+		timeScale:  1,
+	}
+	g.loopCond = sync.NewCond(&g.loopMutex)
 	g.localAI = ai.New(ai.NewGame(module))
 	return &g
 }
 
 // Update updates game.
-// It executes game update loop until game is closed.
+// It executes game update loop until game is stopped.
 func (g *Game) Update() {
+	g.Run(context.Background())
+}
+
+// Run executes game update loop until game is stopped or
+// specified context is done.
+// The game is updated with fixed time step, scaled by the
+// game time scale. While the game is paused the loop
+// is blocked.
+func (g *Game) Run(ctx context.Context) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			g.Stop()
+		case <-done:
+		}
+	}()
+	ticker := time.NewTicker(stepTime)
+	defer ticker.Stop()
 	update := time.Now()
-	for !g.closing {
-		if g.pause {
-			continue
+	accumulator := time.Duration(0)
+	for {
+		paused := g.Pause()
+		if !g.waitUnpaused() {
+			return
 		}
-		delta := time.Since(update).Milliseconds()
-		g.Module.Update(delta)
-		if g.Server() == nil {
-			g.updateAIChars()
-			g.localAI.Update(delta)
+		if paused {
+			// Skip time spent in pause.
+			update = time.Now()
 		}
-		g.updateChars()
-		update = time.Now()
-		time.Sleep(time.Duration(16) * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		accumulator += time.Duration(float64(now.Sub(update)) * g.TimeScale())
+		update = now
+		if accumulator > maxSteps*stepTime {
+			accumulator = maxSteps * stepTime
+		}
+		for accumulator >= stepTime {
+			g.Step(stepTime.Milliseconds())
+			accumulator -= stepTime
+		}
+		g.flushRequests()
+	}
+}
+
+// Step executes single game update with specified
+// time delta in milliseconds.
+func (g *Game) Step(delta int64) {
+	var expired []*PendingOp
+	updateMutex.Lock()
+	g.Module.Update(delta)
+	g.updateFollowers()
+	g.updateWaypoints()
+	if g.Server() == nil {
+		g.updateCompanions(delta)
+		g.updateAIChars()
+		g.localAI.Update(delta)
+	} else {
+		g.updateMovement(time.Now())
+		expired = g.expirePendingOps()
+	}
+	g.updateChars()
+	updateMutex.Unlock()
+	for _, op := range expired {
+		g.pendingRolledBack(op)
+	}
+}
+
+// Stop stops the game update loop.
+func (g *Game) Stop() {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
+	g.closing = true
+	g.loopCond.Broadcast()
+}
+
+// TimeScale returns current game time scale.
+func (g *Game) TimeScale() float64 {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
+	return g.timeScale
+}
+
+// SetTimeScale sets game time scale, e.g. 2 makes the game
+// time to pass two times faster.
+// Time scale can't be changed while playing on the remote
+// server.
+func (g *Game) SetTimeScale(scale float64) error {
+	if g.Server() != nil {
+		return fmt.Errorf("Unable to change time scale on the remote server")
+	}
+	if !slices.Contains(TimeScales(), scale) {
+		return fmt.Errorf("Unsupported time scale: %v", scale)
 	}
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
+	g.timeScale = scale
+	return nil
+}
+
+// Events returns game events bus.
+func (g *Game) Events() *EventBus {
+	return g.events
+}
+
+// SetChapter sets specified chapter as current chapter.
+func (g *Game) SetChapter(chapter *flame.Chapter) {
+	g.Module.SetChapter(chapter)
+	g.events.Emit(ChapterChangedEvent{chapter})
 }
 
 // Char returns game character with specified ID and serial.
 func (g *Game) Char(id, serial string) *Character {
-	return g.chars[id+serial]
+	return g.chars.char(id, serial)
+}
+
+// RangeChars calls specified function for each game character,
+// until the function returns false.
+func (g *Game) RangeChars(f func(c *Character) bool) {
+	g.chars.rangeChars(f)
 }
 
-// AddPlayerChar adds specified character to player characters list.
+// AddPlayerChar adds specified character to player characters list
+// and sets it as active player character.
 func (g *Game) AddPlayerChar(char *Character) error {
-	g.playerChars = append(g.playerChars, char)
+	if !g.chars.addPlayer(char) {
+		return fmt.Errorf("Character is already a player character: %s %s",
+			char.ID(), char.Serial())
+	}
 	g.SetActivePlayerChar(char)
 	return nil
 }
 
 // PlayerChars returns all player characters.
+// Returned slice is a copy, so it's safe to modify.
 func (g *Game) PlayerChars() []*Character {
-	return g.playerChars
+	return g.chars.playerChars()
 }
 
 // SetActivePlayer sets specified avatar as active player avatar.
 func (g *Game) SetActivePlayerChar(char *Character) {
-	g.activePC = char
+	g.chars.setActiveChar(char)
 	if g.onPlayerCharChange != nil {
 		g.onPlayerCharChange(char)
 	}
+	g.events.Emit(ActivePlayerCharChangedEvent{char})
 }
 
 // ActivePlayerChar returns active player character.
 func (g *Game) ActivePlayerChar() *Character {
-	return g.activePC
+	return g.chars.activeChar()
 }
 
 // Pause checks if the game pause is active.
 func (g *Game) Pause() bool {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
 	return g.pause
 }
 
@@ -128,7 +271,11 @@ func (g *Game) Pause() bool {
 // the pause request to the server and return, changing the game
 // pause variable value should happend while handling the server
 // response in such a case.
+// Spectator can't pause the game.
 func (g *Game) SetPause(pause bool) {
+	if g.Spectator() {
+		return
+	}
 	if g.server != nil {
 		req := request.Request{Pause: pause}
 		err := g.server.Send(req)
@@ -137,8 +284,7 @@ func (g *Game) SetPause(pause bool) {
 		}
 		return
 	}
-	g.pause = pause
-	log.Inf.Print(pauseMessage(g.pause))
+	g.setPause(pause)
 }
 
 // SetServer sets game server.
@@ -146,6 +292,7 @@ func (g *Game) SetServer(server *Server) {
 	g.server = server
 	if g.Server() != nil {
 		g.Server().SetOnResponseFunc(g.handleResponse)
+		g.Server().SetOnStateChangeFunc(g.onServerStateChange)
 	}
 }
 
@@ -154,8 +301,22 @@ func (g *Game) Server() *Server {
 	return g.server
 }
 
+// SetSpectator toggles spectator mode.
+// In spectator mode the game is only watched, without any
+// player characters, and everything is visible for the player.
+func (g *Game) SetSpectator(spectator bool) {
+	g.spectator.Store(spectator)
+}
+
+// Spectator checks if the game is in spectator mode.
+func (g *Game) Spectator() bool {
+	return g.spectator.Load()
+}
+
 // Closing checks if game should be closed.
 func (g *Game) Closing() bool {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
 	return g.closing
 }
 
@@ -164,6 +325,12 @@ func (g *Game) SetOnPlayerCharChangeFunc(f func(c *Character)) {
 	g.onPlayerCharChange = f
 }
 
+// SetOnChapterChangeFunc sets function triggered after
+// the game server changed the current chapter.
+func (g *Game) SetOnChapterChangeFunc(f func(c *flame.Chapter)) {
+	g.onChapterChange = f
+}
+
 // SpawnChar sets start area and position of current chapter for specified
 // character.
 func (g *Game) SpawnChar(char *Character) error {
@@ -183,17 +350,23 @@ func (g *Game) SpawnChar(char *Character) error {
 // TransferItems transfer items between specified objects.
 // Items are in the form of a map with IDs as keys and serial values as values.
 func (g *Game) TransferItems(from, to item.Container, items ...item.Item) error {
+	moves := make([]itemMove, 0)
 	for _, i := range items {
 		if from.Inventory().Item(i.ID(), i.Serial()) == nil {
 			return fmt.Errorf("Item not found: %s %s",
 				i.ID(), i.Serial())
 		}
+		moves = append(moves, newItemMove(i, from, to))
 		from.Inventory().RemoveItem(i)
 		to.Inventory().AddItem(i)
 	}
+	event := ItemsTransferredEvent{from, to, items}
 	if g.Server() == nil {
+		g.events.Emit(event)
 		return nil
 	}
+	confirm := func() { g.events.Emit(event) }
+	op := g.addPendingOp(PendingTransfer, confirm, moves...)
 	transferReq := request.TransferItems{
 		ObjectFromID:     from.ID(),
 		ObjectFromSerial: from.Serial(),
@@ -205,30 +378,50 @@ func (g *Game) TransferItems(from, to item.Container, items ...item.Item) error
 		transferReq.Items[i.ID()] = append(transferReq.Items[i.ID()], i.Serial())
 	}
 	req := request.Request{TransferItems: []request.TransferItems{transferReq}}
-	err := g.Server().Send(req)
+	err := g.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Game: transfer items: unable to send transfer items request: %v",
 			err)
+		g.cancelPendingOp(op)
 	}
 	return nil
 }
 
 // Trade exchanges items between specified containers.
+// Items are exchanged only if the trade is fair for the seller
+// pricing.
+// Sold items are added to the seller buyback list, with the game
+// server after the server confirmed the trade.
 func (g *Game) Trade(seller, buyer item.Container, sellItems, buyItems []item.Item) {
-	if !fairTrade(sellItems, buyItems) {
+	pricing := g.Pricing(seller, buyer)
+	if !pricing.Fair(sellItems, buyItems) {
 		return
 	}
+	buyback := make([]BuybackItem, len(sellItems))
+	for i, it := range sellItems {
+		buyback[i] = BuybackItem{it, pricing.SellPrice(it)}
+	}
+	moves := make([]itemMove, 0)
 	for _, it := range sellItems {
+		moves = append(moves, newItemMove(it, buyer, seller))
 		buyer.Inventory().RemoveItem(it)
 		seller.Inventory().AddItem(it)
 	}
 	for _, it := range buyItems {
+		moves = append(moves, newItemMove(it, seller, buyer))
 		seller.Inventory().RemoveItem(it)
 		buyer.Inventory().AddItem(it)
 	}
+	confirm := func() {
+		g.removeBuyback(seller, buyItems...)
+		g.addBuyback(seller, buyback...)
+		g.events.Emit(TradeCompletedEvent{seller, buyer, sellItems, buyItems})
+	}
 	if g.Server() == nil {
+		confirm()
 		return
 	}
+	op := g.addPendingOp(PendingTrade, confirm, moves...)
 	transferReqSell := request.TransferItems{
 		ObjectFromID:     buyer.ID(),
 		ObjectFromSerial: buyer.Serial(),
@@ -255,12 +448,14 @@ func (g *Game) Trade(seller, buyer item.Container, sellItems, buyItems []item.It
 	if err != nil {
 		log.Err.Printf("Game: trade items: unable to send trade request: %v",
 			err)
+		g.cancelPendingOp(op)
 	}
 }
 
 // StartDialog starts dialog with specified object as dialog target.
 func (g *Game) StartDialog(dialog *dialog.Dialog, target dialog.Talker) {
 	dialog.SetTarget(target)
+	g.events.Emit(DialogStartedEvent{dialog, target})
 	if g.Server() == nil || dialog.Owner() == nil {
 		return
 	}
@@ -272,7 +467,7 @@ func (g *Game) StartDialog(dialog *dialog.Dialog, target dialog.Talker) {
 		DialogID:     dialog.ID(),
 	}
 	req := request.Request{Dialog: []request.Dialog{dialogReq}}
-	err := g.Server().Send(req)
+	err := g.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Game: start dialog: unable to send dialog request: %v",
 			err)
@@ -282,6 +477,7 @@ func (g *Game) StartDialog(dialog *dialog.Dialog, target dialog.Talker) {
 // EndDialog ends specified dialog.
 func (g *Game) EndDialog(dialog *dialog.Dialog) {
 	defer dialog.SetTarget(nil)
+	defer g.events.Emit(DialogEndedEvent{dialog})
 	if g.Server() == nil || dialog.Owner() == nil {
 		return
 	}
@@ -293,7 +489,7 @@ func (g *Game) EndDialog(dialog *dialog.Dialog) {
 		DialogID:     dialog.ID(),
 	}
 	req := request.Request{DialogEnd: []request.DialogEnd{dialogReq}}
-	err := g.Server().Send(req)
+	err := g.Server().Queue(req)
 	if err != nil {
 		log.Err.Printf("Game: end dialog: unable to send end dialog request: %v",
 			err)
@@ -315,18 +511,23 @@ func (g *Game) AnswerDialog(dialog *dialog.Dialog, answer *dialog.Answer) {
 			AnswerID: answer.ID(),
 		}
 		req := request.Request{DialogAnswer: []request.DialogAnswer{dialogAnswerReq}}
-		err := g.Server().Send(req)
+		err := g.Server().Queue(req)
 		if err != nil {
 			log.Err.Printf("Game: answer dialog: unable to send dialog answer: %v",
 				err)
 		}
 	}
 	dialog.Next(answer)
+	g.events.Emit(DialogAnsweredEvent{dialog, answer})
 }
 
 // VisibleForPlayer checks whether specified position is
 // in visibility range of any PC.
+// In spectator mode all positions are visible.
 func (g *Game) VisibleForPlayer(x, y float64) bool {
+	if g.Spectator() {
+		return true
+	}
 	for _, pc := range g.PlayerChars() {
 		if pc.InSight(x, y) {
 			return true
@@ -336,53 +537,124 @@ func (g *Game) VisibleForPlayer(x, y float64) bool {
 }
 
 // updateAIChars updates list of characters controlled by the AI.
+// Companions are controlled by the companion AI, so they are
+// never added to the list.
 func (g *Game) updateAIChars() {
-outer:
-	for _, c := range g.chars {
+	g.RangeChars(func(c *Character) bool {
 		for _, aic := range g.localAI.Game().Characters() {
 			if aic.ID() == c.ID() && aic.Serial() == c.Serial() {
-				continue outer
+				return true
 			}
 		}
-		if !c.HasFlag(aiCharFlag) {
-			continue
+		if !c.HasFlag(aiCharFlag) || g.Companion(c) {
+			return true
 		}
 		aiChar := ai.NewCharacter(c.Character, g.localAI.Game())
+		aiChar.AddOnUseEvent(c.used)
 		g.localAI.Game().AddCharacter(aiChar)
-	}
+		return true
+	})
 }
 
 // updateChars updates list of game characters.
+// Characters removed from the current chapter are removed
+// from the list, unless they are player characters.
 func (g *Game) updateChars() {
-	for _, p := range g.playerChars {
-		gameChar := g.chars[p.ID()+p.Serial()]
-		if gameChar != nil {
+	chapterChars := g.Chapter().Characters()
+	for _, c := range chapterChars {
+		if g.Char(c.ID(), c.Serial()) != nil {
 			continue
 		}
-		g.chars[p.ID()+p.Serial()] = p
+		char := g.chars.addChar(NewCharacter(c, g))
+		g.events.Emit(CharSpawnedEvent{char})
+	}
+	if len(chapterChars) == g.chars.size() {
+		return
 	}
-	for _, c := range g.Chapter().Characters() {
-		gameChar := g.chars[c.ID()+c.Serial()]
-		if gameChar != nil {
+	present := make(map[string]bool)
+	for _, c := range chapterChars {
+		present[c.ID()+c.Serial()] = true
+	}
+	g.RangeChars(func(c *Character) bool {
+		if present[c.ID()+c.Serial()] {
+			return true
+		}
+		if g.chars.removeChar(c) {
+			g.events.Emit(CharDespawnedEvent{c})
+		}
+		return true
+	})
+}
+
+// rebindChars binds player characters to the module characters
+// from the current chapter and rebuilds list of game characters.
+func (g *Game) rebindChars() {
+	for _, p := range g.PlayerChars() {
+		char := g.Chapter().Character(p.ID(), p.Serial())
+		if char == nil {
+			log.Err.Printf("Game: player character not found in new chapter: %s %s",
+				p.ID(), p.Serial())
 			continue
 		}
-		gameChar = NewCharacter(c, g)
-		g.chars[gameChar.ID()+gameChar.Serial()] = gameChar
+		p.moveMutex.Lock()
+		p.Character = char
+		p.prediction = nil
+		p.smoothing = nil
+		p.interpolation = nil
+		p.moveMutex.Unlock()
+	}
+	for _, c := range g.chars.reset() {
+		g.events.Emit(CharDespawnedEvent{c})
+	}
+	g.updateChars()
+}
+
+// onServerStateChange handles server connection state change.
+func (g *Game) onServerStateChange(state ConnState) {
+	g.events.Emit(ServerStateChangedEvent{state})
+}
+
+// setPause sets game pause and wakes up the update loop
+// if the game was unpaused.
+func (g *Game) setPause(pause bool) {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
+	if g.pause == pause {
+		return
 	}
+	g.pause = pause
+	log.Inf.Print(pauseMessage(g.pause))
+	g.loopCond.Broadcast()
 }
 
-// fairTrade checks if value of all items to sell is greater or
-// equal to the value of items to buy.
-func fairTrade(sell, buy []item.Item) bool {
-	buyValue := 0
-	for _, it := range buy {
-		buyValue += it.Value()
+// waitUnpaused blocks until the game is unpaused or stopped.
+// Returns false if the game was stopped.
+func (g *Game) waitUnpaused() bool {
+	g.loopMutex.Lock()
+	defer g.loopMutex.Unlock()
+	for g.pause && !g.closing {
+		g.loopCond.Wait()
 	}
-	sellValue := 0
-	for _, it := range sell {
-		sellValue += it.Value()
+	return !g.closing
+}
+
+// flushRequests sends all requests queued since the last
+// game update to the server.
+// Requests stay in the queue while the server is not
+// connected.
+func (g *Game) flushRequests() {
+	if g.Server() == nil || g.Server().State() != Connected {
+		return
 	}
-	return sellValue >= buyValue
+	err := g.Server().Flush()
+	if err != nil {
+		log.Err.Printf("Game: unable to send queued requests: %v", err)
+	}
+}
+
+// TimeScales returns all supported game time scales.
+func TimeScales() []float64 {
+	return []float64{0.5, 1, 2, 4}
 }
 
 // pauseMessage returns the pause message depending on the pause
@@ -0,0 +1,92 @@
+/*
+ * game_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestGameRunPaused tests stopping paused game update loop.
+func TestGameRunPaused(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	game.SetPause(true)
+	// Test.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		game.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Game loop not stopped after context cancel")
+	}
+	if !game.Closing() {
+		t.Errorf("Game not closing after context cancel")
+	}
+}
+
+// TestGameSetTimeScale tests setting game time scale.
+func TestGameSetTimeScale(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	// Test.
+	err := game.SetTimeScale(2)
+	if err != nil {
+		t.Fatalf("Unable to set time scale: %v", err)
+	}
+	if game.TimeScale() != 2 {
+		t.Errorf("Invalid time scale: %f != 2", game.TimeScale())
+	}
+	err = game.SetTimeScale(3)
+	if err == nil {
+		t.Errorf("No error for unsupported time scale")
+	}
+}
+
+// TestGameSpectator tests game in spectator mode.
+func TestGameSpectator(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	game.SetSpectator(true)
+	// Test.
+	if !game.VisibleForPlayer(100, 100) {
+		t.Errorf("Position not visible for spectator")
+	}
+	game.SetPause(true)
+	if game.Pause() {
+		t.Errorf("Game paused by spectator")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * loopback.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+package game
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// Struct for in-memory loopback transport.
+// Loopback doesn't need any server, all sent requests are
+// recorded and passed to the request handler, responses returned
+// by the handler or scripted with the Respond function are received
+// by the server connection in the same order.
+type Loopback struct {
+	mutex     sync.Mutex
+	requests  []request.Request
+	responses chan response.Response
+	closed    chan struct{}
+	closeOnce sync.Once
+	onRequest func(req request.Request) []response.Response
+}
+
+var (
+	loopbackQueueSize = 100
+	errLoopbackClosed = errors.New("Loopback closed")
+)
+
+// NewLoopback creates new loopback transport.
+func NewLoopback() *Loopback {
+	l := Loopback{
+		responses: make(chan response.Response, loopbackQueueSize),
+		closed:    make(chan struct{}),
+	}
+	return &l
+}
+
+// SetOnRequestFunc sets function triggered for each sent request,
+// all responses returned by this function are queued as responses
+// from the server.
+func (l *Loopback) SetOnRequestFunc(f func(req request.Request) []response.Response) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.onRequest = f
+}
+
+// Respond queues specified responses as responses from the server.
+func (l *Loopback) Respond(resps ...response.Response) error {
+	for _, r := range resps {
+		select {
+		case <-l.closed:
+			return errLoopbackClosed
+		case l.responses <- r:
+		}
+	}
+	return nil
+}
+
+// Requests returns all requests sent via loopback.
+func (l *Loopback) Requests() []request.Request {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	reqs := make([]request.Request, len(l.requests))
+	copy(reqs, l.requests)
+	return reqs
+}
+
+// Send records specified request and passes it to the request
+// handler function.
+func (l *Loopback) Send(req request.Request) error {
+	select {
+	case <-l.closed:
+		return errLoopbackClosed
+	default:
+	}
+	l.mutex.Lock()
+	l.requests = append(l.requests, req)
+	onRequest := l.onRequest
+	l.mutex.Unlock()
+	if onRequest == nil {
+		return nil
+	}
+	return l.Respond(onRequest(req)...)
+}
+
+// Receive waits for the next queued response.
+func (l *Loopback) Receive() (response.Response, error) {
+	select {
+	case <-l.closed:
+		return response.Response{}, errLoopbackClosed
+	case r := <-l.responses:
+		return r, nil
+	}
+}
+
+// Close closes loopback, all further send and receive
+// calls will return an error.
+func (l *Loopback) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+// Address returns loopback address.
+func (l *Loopback) Address() string {
+	return "loopback"
+}
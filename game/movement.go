@@ -0,0 +1,279 @@
+/*
+ * movement.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+package game
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Max distance between the local and server position
+	// of character that will be corrected smoothly, on
+	// bigger distance character is moved instantly.
+	snapDistance = 100.0
+	// Time of smooth position correction.
+	correctionTime = 200 * time.Millisecond
+	// Max time of character interpolation between two
+	// server updates.
+	maxInterpolationTime = time.Second
+	// Time after which not confirmed move prediction
+	// is dropped.
+	predictionTimeout = time.Second
+	// Max distance between predicted and server destination
+	// point for the prediction to be confirmed.
+	predictionMargin = 1.0
+)
+
+// Struct for smooth character position correction.
+type moveSmoothing struct {
+	offsetX, offsetY   float64
+	appliedX, appliedY float64
+	start              time.Time
+}
+
+// Struct for interpolation of character position between
+// two server updates.
+type moveInterpolation struct {
+	fromX, fromY float64
+	toX, toY     float64
+	start        time.Time
+	duration     time.Duration
+}
+
+// Struct for predicted character move.
+type movePrediction struct {
+	x, y float64
+	time time.Time
+}
+
+// Struct for character position.
+type position struct {
+	x, y float64
+}
+
+// charPositions returns current positions of all game
+// characters.
+func (g *Game) charPositions() map[string]position {
+	positions := make(map[string]position)
+	g.RangeChars(func(c *Character) bool {
+		x, y := c.Position()
+		positions[c.ID()+c.Serial()] = position{x, y}
+		return true
+	})
+	return positions
+}
+
+// correctPositions compares specified local positions of game
+// characters with positions received from the server.
+// The move prediction for player characters with unconfirmed
+// moves is restored, other player characters are moved smoothly
+// from the local position to the server position.
+// Remaining characters are interpolated from the local position
+// to the server position until the next server update.
+func (g *Game) correctPositions(positions map[string]position, now time.Time) {
+	players := make(map[*Character]bool)
+	for _, pc := range g.chars.playerChars() {
+		players[pc] = true
+	}
+	interval := g.updateInterval(now)
+	g.RangeChars(func(c *Character) bool {
+		pos, ok := positions[c.ID()+c.Serial()]
+		if !ok {
+			return true
+		}
+		if !players[c] {
+			c.startInterpolation(pos, now, interval)
+			return true
+		}
+		if !c.restorePrediction(pos, now) {
+			c.startSmoothing(pos, now)
+		}
+		return true
+	})
+}
+
+// updateInterval returns time since the previous server update
+// and saves specified time as time of the last update.
+func (g *Game) updateInterval(now time.Time) time.Duration {
+	interval := now.Sub(g.lastUpdate)
+	if g.lastUpdate.IsZero() || interval <= 0 {
+		interval = correctionTime
+	}
+	if interval > maxInterpolationTime {
+		interval = maxInterpolationTime
+	}
+	g.lastUpdate = now
+	return interval
+}
+
+// updateMovement updates smooth position correction and
+// interpolation for all game characters.
+func (g *Game) updateMovement(now time.Time) {
+	g.RangeChars(func(c *Character) bool {
+		c.updateSmoothing(now)
+		c.updateInterpolation(now)
+		return true
+	})
+}
+
+// predictMove saves specified destination point as predicted
+// move of the character.
+func (c *Character) predictMove(x, y float64, now time.Time) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	c.prediction = &movePrediction{x, y, now}
+}
+
+// restorePrediction restores specified local position and predicted
+// destination point if the predicted move was not yet confirmed
+// by the server.
+// Returns true if the prediction was restored.
+func (c *Character) restorePrediction(pos position, now time.Time) bool {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	if c.prediction == nil {
+		return false
+	}
+	destX, destY := c.DestPoint()
+	if math.Hypot(destX-c.prediction.x, destY-c.prediction.y) <= predictionMargin ||
+		now.Sub(c.prediction.time) > predictionTimeout {
+		c.prediction = nil
+		return false
+	}
+	x, y := c.Position()
+	if math.Hypot(pos.x-x, pos.y-y) > snapDistance {
+		c.prediction = nil
+		return false
+	}
+	c.Character.SetPosition(pos.x, pos.y)
+	c.Character.SetDestPoint(c.prediction.x, c.prediction.y)
+	c.smoothing = nil
+	return true
+}
+
+// startSmoothing starts smooth correction of the character position
+// from the specified local position to the current position.
+func (c *Character) startSmoothing(pos position, now time.Time) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	x, y := c.Position()
+	offsetX, offsetY := pos.x-x, pos.y-y
+	dist := math.Hypot(offsetX, offsetY)
+	if dist == 0 || dist > snapDistance {
+		c.smoothing = nil
+		return
+	}
+	c.smoothing = &moveSmoothing{
+		offsetX: offsetX,
+		offsetY: offsetY,
+		start:   now,
+	}
+	c.applySmoothing(now)
+}
+
+// updateSmoothing updates the correction offset applied to the
+// character position.
+func (c *Character) updateSmoothing(now time.Time) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	c.applySmoothing(now)
+}
+
+// applySmoothing applies the correction offset for specified
+// time to the character position.
+// Move mutex must be locked by the caller.
+func (c *Character) applySmoothing(now time.Time) {
+	if c.smoothing == nil {
+		return
+	}
+	s := c.smoothing
+	x, y := c.Position()
+	x, y = x-s.appliedX, y-s.appliedY
+	progress := moveProgress(s.start, now, correctionTime)
+	s.appliedX = s.offsetX * (1 - progress)
+	s.appliedY = s.offsetY * (1 - progress)
+	c.Character.SetPosition(x+s.appliedX, y+s.appliedY)
+	if progress >= 1 {
+		c.smoothing = nil
+	}
+}
+
+// startInterpolation starts interpolation of the character
+// position from the specified local position to the current
+// position, with specified duration.
+func (c *Character) startInterpolation(pos position, now time.Time, duration time.Duration) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	x, y := c.Position()
+	dist := math.Hypot(pos.x-x, pos.y-y)
+	if dist == 0 || dist > snapDistance {
+		c.interpolation = nil
+		return
+	}
+	c.interpolation = &moveInterpolation{
+		fromX:    pos.x,
+		fromY:    pos.y,
+		toX:      x,
+		toY:      y,
+		start:    now,
+		duration: duration,
+	}
+	c.applyInterpolation(now)
+}
+
+// updateInterpolation updates the interpolated character
+// position.
+func (c *Character) updateInterpolation(now time.Time) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	c.applyInterpolation(now)
+}
+
+// applyInterpolation sets the character position interpolated
+// for specified time.
+// Move mutex must be locked by the caller.
+func (c *Character) applyInterpolation(now time.Time) {
+	if c.interpolation == nil {
+		return
+	}
+	i := c.interpolation
+	progress := moveProgress(i.start, now, i.duration)
+	x := i.fromX + (i.toX-i.fromX)*progress
+	y := i.fromY + (i.toY-i.fromY)*progress
+	c.Character.SetPosition(x, y)
+	if progress >= 1 {
+		c.interpolation = nil
+	}
+}
+
+// moveProgress returns progress of the move started at
+// specified time with specified duration, in range from
+// 0 to 1.
+func moveProgress(start, now time.Time, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 1
+	}
+	progress := float64(now.Sub(start)) / float64(duration)
+	return math.Max(0, math.Min(progress, 1))
+}
@@ -0,0 +1,134 @@
+/*
+ * movement_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestCharPredictionTimeout tests restoring move prediction
+// only until the prediction timeout.
+func TestCharPredictionTimeout(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(res.ModuleData{}))
+	char := NewCharacter(character.New(res.CharacterData{ID: "char", Level: 1}), game)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Test.
+	char.predictMove(50, 0, start)
+	if !char.restorePrediction(position{10, 0}, start.Add(predictionTimeout/2)) {
+		t.Fatalf("Prediction not restored before timeout")
+	}
+	x, y := char.Position()
+	if x != 10 || y != 0 {
+		t.Errorf("Invalid restored position: %fx%f != 10x0", x, y)
+	}
+	x, y = char.DestPoint()
+	if x != 50 || y != 0 {
+		t.Errorf("Invalid restored destination point: %fx%f != 50x0", x, y)
+	}
+	char.Character.SetDestPoint(0, 0)
+	if char.restorePrediction(position{10, 0}, start.Add(predictionTimeout*2)) {
+		t.Errorf("Prediction restored after timeout")
+	}
+	if char.prediction != nil {
+		t.Errorf("Prediction not dropped after timeout")
+	}
+}
+
+// TestCharSmoothing tests smooth correction of the character
+// position.
+func TestCharSmoothing(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(res.ModuleData{}))
+	char := NewCharacter(character.New(res.CharacterData{ID: "char", Level: 1}), game)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Test snap.
+	char.startSmoothing(position{snapDistance + 1, 0}, start)
+	if char.smoothing != nil {
+		t.Errorf("Smoothing started for distance over snap distance")
+	}
+	x, y := char.Position()
+	if x != 0 || y != 0 {
+		t.Errorf("Invalid snapped position: %fx%f != 0x0", x, y)
+	}
+	// Test progress.
+	char.startSmoothing(position{10, 0}, start)
+	x, y = char.Position()
+	if x != 10 || y != 0 {
+		t.Errorf("Invalid position on smoothing start: %fx%f != 10x0", x, y)
+	}
+	char.updateSmoothing(start.Add(correctionTime / 2))
+	x, y = char.Position()
+	if x != 5 || y != 0 {
+		t.Errorf("Invalid position in half of smoothing: %fx%f != 5x0", x, y)
+	}
+	char.updateSmoothing(start.Add(correctionTime))
+	x, y = char.Position()
+	if x != 0 || y != 0 {
+		t.Errorf("Invalid position after smoothing: %fx%f != 0x0", x, y)
+	}
+	if char.smoothing != nil {
+		t.Errorf("Smoothing not finished")
+	}
+}
+
+// TestCharInterpolation tests interpolation of the character
+// position between server updates.
+func TestCharInterpolation(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(res.ModuleData{}))
+	char := NewCharacter(character.New(res.CharacterData{ID: "char", Level: 1}), game)
+	game.chars.addChar(char)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Test.
+	game.updateInterval(start)
+	char.Character.SetPosition(20, 0)
+	game.correctPositions(map[string]position{char.ID() + char.Serial(): {0, 0}},
+		start.Add(100*time.Millisecond))
+	if char.interpolation == nil || char.interpolation.duration != 100*time.Millisecond {
+		t.Fatalf("Invalid interpolation: %v", char.interpolation)
+	}
+	x, y := char.Position()
+	if x != 0 || y != 0 {
+		t.Errorf("Invalid position on interpolation start: %fx%f != 0x0", x, y)
+	}
+	game.updateMovement(start.Add(150 * time.Millisecond))
+	x, y = char.Position()
+	if x != 10 || y != 0 {
+		t.Errorf("Invalid position in half of interpolation: %fx%f != 10x0", x, y)
+	}
+	game.updateMovement(start.Add(200 * time.Millisecond))
+	x, y = char.Position()
+	if x != 20 || y != 0 {
+		t.Errorf("Invalid position after interpolation: %fx%f != 20x0", x, y)
+	}
+	if char.interpolation != nil {
+		t.Errorf("Interpolation not finished")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * netstats.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/log"
+)
+
+var (
+	// Interval between network statistics updates.
+	netStatsInterval = time.Second
+)
+
+// Struct for server connection statistics.
+type NetStats struct {
+	// Round-trip time, zero if the transport doesn't
+	// support ping.
+	RTT time.Duration
+	// Messages per second.
+	SentMsgs     float64
+	ReceivedMsgs float64
+	// Bytes per second, zero if the transport doesn't
+	// count bytes.
+	SentBytes     float64
+	ReceivedBytes float64
+	// Size in bytes of the last server message with
+	// update and time of applying the update.
+	UpdateSize      int64
+	UpdateApplyTime time.Duration
+}
+
+// Struct for traffic counters sample.
+type netSample struct {
+	time          time.Time
+	sent          int64
+	received      int64
+	bytesSent     int64
+	bytesReceived int64
+}
+
+// String returns text with all statistics.
+func (ns NetStats) String() string {
+	return fmt.Sprintf("rtt: %v, out: %.1f msg/s %.1f B/s, in: %.1f msg/s %.1f B/s, "+
+		"update: %d B applied in %v", ns.RTT, ns.SentMsgs, ns.SentBytes,
+		ns.ReceivedMsgs, ns.ReceivedBytes, ns.UpdateSize, ns.UpdateApplyTime)
+}
+
+// Stats returns current statistics of the server connection.
+func (s *Server) Stats() NetStats {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	return s.stats
+}
+
+// Received returns number of messages received from the
+// server.
+func (s *Server) Received() int64 {
+	return s.received.Load()
+}
+
+// recordUpdate records apply time of the last update
+// response.
+func (s *Server) recordUpdate(applyTime time.Duration) {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	s.stats.UpdateApplyTime = applyTime
+}
+
+// recordUpdateSize records size of the last message with
+// update received from the server.
+func (s *Server) recordUpdateSize(size int64) {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	s.stats.UpdateSize = size
+}
+
+// monitor pings the server and updates connection statistics
+// in regular intervals, until the server is closed.
+// If configured, statistics are also logged periodically.
+func (s *Server) monitor() {
+	ticker := time.NewTicker(netStatsInterval)
+	defer ticker.Stop()
+	lastLog := time.Now()
+	for range ticker.C {
+		if s.isClosed() {
+			return
+		}
+		s.mutex.RLock()
+		conn := s.conn
+		state := s.state
+		s.mutex.RUnlock()
+		if pinger, ok := conn.(PingTransport); ok && state == Connected {
+			err := pinger.Ping()
+			if err != nil {
+				log.Err.Printf("Server: %s: unable to ping: %v", s.Address(), err)
+			}
+		}
+		s.updateStats(conn)
+		logInterval := time.Duration(config.ServerStatsLog) * time.Second
+		if logInterval > 0 && time.Since(lastLog) >= logInterval {
+			log.Inf.Printf("Server: %s: %s", s.Address(), s.Stats())
+			lastLog = time.Now()
+		}
+	}
+}
+
+// updateStats updates connection statistics with traffic
+// counters of specified transport.
+func (s *Server) updateStats(conn Transport) {
+	sample := netSample{
+		time:     time.Now(),
+		sent:     s.Sent(),
+		received: s.Received(),
+	}
+	if counter, ok := conn.(CountingTransport); ok {
+		sample.bytesSent = counter.BytesSent()
+		sample.bytesReceived = counter.BytesReceived()
+	}
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	if pinger, ok := conn.(PingTransport); ok {
+		s.stats.RTT = pinger.RTT()
+	}
+	last := s.lastSample
+	s.lastSample = sample
+	secs := sample.time.Sub(last.time).Seconds()
+	if last.time.IsZero() || secs <= 0 {
+		return
+	}
+	s.stats.SentMsgs = float64(sample.sent-last.sent) / secs
+	s.stats.ReceivedMsgs = float64(sample.received-last.received) / secs
+	// Byte counters are reset after reconnect.
+	if sample.bytesSent >= last.bytesSent {
+		s.stats.SentBytes = float64(sample.bytesSent-last.bytesSent) / secs
+	}
+	if sample.bytesReceived >= last.bytesReceived {
+		s.stats.ReceivedBytes = float64(sample.bytesReceived-last.bytesReceived) / secs
+	}
+}
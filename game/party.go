@@ -0,0 +1,194 @@
+/*
+ * party.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Distance between characters in party formation.
+	formationSpacing = 32.0
+	// Max distance of the follower destination from
+	// its formation position.
+	followMargin = formationSpacing / 2
+	// Number of characters in formation row.
+	formationRowSize = 3
+)
+
+var (
+	// Time after failed search for path to the leader,
+	// before the follower searches for path again.
+	followRetryDelay = time.Second
+)
+
+// Struct for state of the character following the leader.
+// Used only by the game update step.
+type followState struct {
+	pathed  bool
+	leaderX float64
+	leaderY float64
+	retry   time.Time
+}
+
+// SetFollowLeader toggles follow mode for the player party.
+// In follow mode all non-active player characters follow
+// the active player character in formation.
+func (g *Game) SetFollowLeader(follow bool) {
+	g.followLeader.Store(follow)
+}
+
+// FollowLeader checks if follow mode for the player party
+// is enabled.
+func (g *Game) FollowLeader() bool {
+	return g.followLeader.Load()
+}
+
+// MoveGroup moves specified characters to specified position
+// in formation.
+// The first character is the leader of the group and moves
+// exactly to specified position.
+// The move is a player order, companions AI doesn't steer
+// the characters until they reach the destination.
+func (g *Game) MoveGroup(chars []*Character, x, y float64) {
+	for i, c := range chars {
+		c.setOrdered()
+		if i == 0 {
+			c.MoveTo(x, y)
+			continue
+		}
+		offX, offY := formationOffset(i - 1)
+		if !c.MoveTo(x+offX, y+offY) {
+			c.MoveTo(x, y)
+		}
+	}
+}
+
+// updateFollowers moves all non-active player characters to
+// their formation positions behind the active player character,
+// if follow mode is enabled.
+func (g *Game) updateFollowers() {
+	if !g.FollowLeader() {
+		return
+	}
+	leader := g.ActivePlayerChar()
+	if leader == nil {
+		return
+	}
+	leaderArea := g.Chapter().ObjectArea(leader)
+	if leaderArea == nil {
+		return
+	}
+	i := 0
+	for _, c := range g.PlayerChars() {
+		if c == leader {
+			continue
+		}
+		slot := i
+		i++
+		// Companions follow the leader on their own.
+		if g.Companion(c) {
+			continue
+		}
+		area := g.Chapter().ObjectArea(c)
+		if area == nil || area.ID() != leaderArea.ID() {
+			continue
+		}
+		c.followLeader(leader, slot)
+	}
+}
+
+// followLeader moves the character to its formation position
+// behind the leader, if the character is too far from it.
+// New path is searched only if the leader moved since the last
+// search. After failed search, the search is not repeated until
+// the retry delay passes.
+func (c *Character) followLeader(leader *Character, slot int) {
+	leaderX, leaderY := leader.Position()
+	if c.follow.pathed && math.Hypot(leaderX-c.follow.leaderX,
+		leaderY-c.follow.leaderY) <= followMargin {
+		return
+	}
+	if time.Now().Before(c.follow.retry) {
+		return
+	}
+	offX, offY := formationOffset(slot)
+	slotX, slotY := leaderX+offX, leaderY+offY
+	destX, destY := c.finalDestPoint()
+	if math.Hypot(destX-slotX, destY-slotY) <= followMargin ||
+		math.Hypot(destX-leaderX, destY-leaderY) <= followMargin {
+		return
+	}
+	c.follow.pathed = true
+	c.follow.leaderX, c.follow.leaderY = leaderX, leaderY
+	if c.MoveTo(slotX, slotY) || c.MoveTo(leaderX, leaderY) {
+		return
+	}
+	c.follow.retry = time.Now().Add(followRetryDelay)
+}
+
+// setOrdered marks the current character movement as
+// ordered by the player.
+func (c *Character) setOrdered() {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	c.ordered = true
+}
+
+// orderPending checks if the character still moves to the
+// destination ordered by the player.
+func (c *Character) orderPending() bool {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	if !c.ordered {
+		return false
+	}
+	posX, posY := c.Position()
+	destX, destY := c.DestPoint()
+	if len(c.waypoints) < 1 && math.Hypot(destX-posX, destY-posY) <= waypointMargin {
+		c.ordered = false
+	}
+	return c.ordered
+}
+
+// finalDestPoint returns the last point of the character path.
+func (c *Character) finalDestPoint() (float64, float64) {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	if len(c.waypoints) > 0 {
+		wp := c.waypoints[len(c.waypoints)-1]
+		return wp.X, wp.Y
+	}
+	return c.DestPoint()
+}
+
+// formationOffset returns position offset from the group
+// leader for the group member with specified index.
+// Members are placed in rows behind the leader.
+func formationOffset(i int) (float64, float64) {
+	row := i/formationRowSize + 1
+	col := i%formationRowSize - formationRowSize/2
+	return float64(col) * formationSpacing, -float64(row) * formationSpacing
+}
@@ -0,0 +1,89 @@
+/*
+ * party_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+)
+
+// Struct for test path finder without any paths.
+type testPathFinder struct {
+	searches int
+}
+
+// ID returns ID of the test area.
+func (pf *testPathFinder) ID() string {
+	return "area"
+}
+
+// Path counts path searches and returns nil.
+func (pf *testPathFinder) Path(fromX, fromY, toX, toY float64) []Waypoint {
+	pf.searches++
+	return nil
+}
+
+// TestGameFollowLeader tests searching for path to the leader
+// only after the leader moves.
+func TestGameFollowLeader(t *testing.T) {
+	followRetryDelay = 0
+	defer func() { followRetryDelay = time.Second }()
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	mod.Chapter().AddAreas(area.New(res.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	game := New(mod)
+	pf := new(testPathFinder)
+	game.SetPathFinder(pf)
+	game.SetFollowLeader(true)
+	// Create characters.
+	follower := NewCharacter(character.New(res.CharacterData{ID: "follower", Level: 1}), game)
+	leader := NewCharacter(character.New(res.CharacterData{ID: "leader", Level: 1}), game)
+	for _, c := range []*Character{follower, leader} {
+		err := game.SpawnChar(c)
+		if err != nil {
+			t.Fatalf("Unable to spawn character: %v", err)
+		}
+		game.AddPlayerChar(c)
+	}
+	// Test.
+	leader.SetPosition(100, 100)
+	for i := 0; i < 10; i++ {
+		game.updateFollowers()
+	}
+	if pf.searches != 2 {
+		t.Errorf("Invalid number of path searches: %d != 2", pf.searches)
+	}
+	leader.SetPosition(200, 200)
+	game.updateFollowers()
+	if pf.searches != 4 {
+		t.Errorf("Invalid number of path searches after leader move: %d != 4",
+			pf.searches)
+	}
+}
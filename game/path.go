@@ -0,0 +1,130 @@
+/*
+ * path.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"math"
+)
+
+const (
+	// Max distance from waypoint to consider it as reached.
+	waypointMargin = 1.0
+)
+
+// Struct for movement waypoint.
+type Waypoint struct {
+	X, Y float64
+}
+
+// Interface for path finder of game area with
+// specified ID.
+type PathFinder interface {
+	ID() string
+	Path(fromX, fromY, toX, toY float64) []Waypoint
+}
+
+// SetPathFinder sets path finder used for player characters
+// movement.
+func (g *Game) SetPathFinder(pf PathFinder) {
+	g.pathMutex.Lock()
+	defer g.pathMutex.Unlock()
+	g.pathFinder = pf
+}
+
+// PathFinder returns current path finder.
+func (g *Game) PathFinder() PathFinder {
+	g.pathMutex.Lock()
+	defer g.pathMutex.Unlock()
+	return g.pathFinder
+}
+
+// updateWaypoints moves all game characters that reached their
+// current waypoints to the next waypoints.
+func (g *Game) updateWaypoints() {
+	g.RangeChars(func(c *Character) bool {
+		c.updateWaypoints()
+		return true
+	})
+}
+
+// MoveTo moves character to specified position, around
+// all obstacles on the way.
+// If there is no path finder for the character area the
+// character moves in straight line.
+// Returns false if there is no path to specified position.
+func (c *Character) MoveTo(x, y float64) bool {
+	pf := c.game.PathFinder()
+	area := c.game.Chapter().ObjectArea(c)
+	if pf == nil || area == nil || area.ID() != pf.ID() {
+		c.SetDestPoint(x, y)
+		return true
+	}
+	posX, posY := c.Position()
+	path := pf.Path(posX, posY, x, y)
+	if len(path) < 1 {
+		return false
+	}
+	c.moveMutex.Lock()
+	c.waypoints = path[1:]
+	c.moveMutex.Unlock()
+	c.setDestPoint(path[0].X, path[0].Y)
+	return true
+}
+
+// Waypoints returns all remaining waypoints of the
+// character path.
+func (c *Character) Waypoints() []Waypoint {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	waypoints := make([]Waypoint, len(c.waypoints))
+	copy(waypoints, c.waypoints)
+	return waypoints
+}
+
+// updateWaypoints sets next waypoint as character destination
+// point if the current one was reached.
+func (c *Character) updateWaypoints() {
+	c.moveMutex.Lock()
+	if len(c.waypoints) < 1 {
+		c.moveMutex.Unlock()
+		return
+	}
+	posX, posY := c.Position()
+	destX, destY := c.DestPoint()
+	if math.Hypot(destX-posX, destY-posY) > waypointMargin {
+		c.moveMutex.Unlock()
+		return
+	}
+	next := c.waypoints[0]
+	c.waypoints = c.waypoints[1:]
+	c.moveMutex.Unlock()
+	c.setDestPoint(next.X, next.Y)
+}
+
+// clearWaypoints removes all waypoints of the character path.
+func (c *Character) clearWaypoints() {
+	c.moveMutex.Lock()
+	defer c.moveMutex.Unlock()
+	c.waypoints = nil
+}
@@ -0,0 +1,394 @@
+/*
+ * pending.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/mural/log"
+)
+
+// Type for kinds of pending operations.
+type PendingKind int
+
+const (
+	PendingTransfer PendingKind = iota
+	PendingTrade
+	PendingThrow
+)
+
+const (
+	// Prefix of the server error messages for failed
+	// requests, followed by the request name.
+	requestErrorPrefix = "unable to handle "
+)
+
+var (
+	// Time after which pending operation without server
+	// confirmation is rolled back.
+	pendingTimeout = 5 * time.Second
+)
+
+// Struct for item operation applied locally and
+// waiting for server confirmation.
+type PendingOp struct {
+	id      int64
+	kind    PendingKind
+	time    time.Time
+	moves   []itemMove
+	confirm func()
+	done    bool
+}
+
+// Struct for single item move between containers.
+// Nil destination container means that item was
+// removed from the source container.
+type itemMove struct {
+	item  item.Item
+	from  item.Container
+	to    item.Container
+	loot  bool
+	price int
+}
+
+// newItemMove creates new move of specified item, loot and
+// trade state of the item is taken from the source inventory,
+// so it could be restored on rollback.
+func newItemMove(it item.Item, from, to item.Container) itemMove {
+	m := itemMove{item: it, from: from, to: to}
+	if invIt := from.Inventory().Item(it.ID(), it.Serial()); invIt != nil {
+		m.loot = invIt.Loot
+		m.price = invIt.Price
+	}
+	return m
+}
+
+// requestName returns name of the server request sent for
+// operations of this kind, as used in the server error
+// messages.
+func (pk PendingKind) requestName() string {
+	switch pk {
+	case PendingTrade:
+		return "trade"
+	case PendingThrow:
+		return "throw items"
+	default:
+		return "transfer items"
+	}
+}
+
+// errorKind returns kind of the operation which request failed
+// with specified server error message.
+// Server error messages for failed requests start with the
+// request name, e.g. 'unable to handle trade request: [reason]'.
+// Returns false if the error is not caused by any operation
+// request.
+func errorKind(errMsg string) (PendingKind, bool) {
+	msg := strings.ToLower(strings.TrimSpace(errMsg))
+	if !strings.HasPrefix(msg, requestErrorPrefix) {
+		return 0, false
+	}
+	msg = strings.TrimPrefix(msg, requestErrorPrefix)
+	for _, k := range []PendingKind{PendingTransfer, PendingTrade, PendingThrow} {
+		if strings.HasPrefix(msg, k.requestName()+" request") {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// errorWords returns set of all words from specified server
+// error message, used to find items named in the message,
+// e.g. as 'sword#1'.
+func errorWords(errMsg string) map[string]bool {
+	separator := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	}
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(errMsg, separator) {
+		words[w] = true
+	}
+	return words
+}
+
+// ID returns operation ID.
+func (op *PendingOp) ID() int64 {
+	return op.id
+}
+
+// Kind returns operation kind.
+func (op *PendingOp) Kind() PendingKind {
+	return op.kind
+}
+
+// Items returns all items moved by the operation.
+func (op *PendingOp) Items() (items []item.Item) {
+	for _, m := range op.moves {
+		items = append(items, m.item)
+	}
+	return
+}
+
+// named checks if any item of the operation is named in
+// the server error message with specified words.
+func (op *PendingOp) named(words map[string]bool) bool {
+	for _, m := range op.moves {
+		if words[m.item.ID()] && words[m.item.Serial()] {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmed checks if current state of containers
+// matches the operation result.
+func (op *PendingOp) confirmed() bool {
+	for _, m := range op.moves {
+		if m.from.Inventory().Item(m.item.ID(), m.item.Serial()) != nil {
+			return false
+		}
+		if m.to != nil && m.to.Inventory().Item(m.item.ID(), m.item.Serial()) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// apply moves all operation items to the destination
+// containers, items already moved are skipped.
+func (op *PendingOp) apply() {
+	for _, m := range op.moves {
+		it := m.from.Inventory().Item(m.item.ID(), m.item.Serial())
+		if it != nil {
+			m.from.Inventory().RemoveItem(it)
+		}
+		if m.to != nil && m.to.Inventory().Item(m.item.ID(), m.item.Serial()) == nil {
+			m.to.Inventory().AddItem(m.item)
+		}
+	}
+}
+
+// rollback moves all operation items back to the source
+// containers, items already moved back are skipped.
+func (op *PendingOp) rollback() {
+	for i := len(op.moves) - 1; i >= 0; i-- {
+		m := op.moves[i]
+		if m.to != nil {
+			it := m.to.Inventory().Item(m.item.ID(), m.item.Serial())
+			if it != nil {
+				m.to.Inventory().RemoveItem(it)
+			}
+		}
+		if m.from.Inventory().Item(m.item.ID(), m.item.Serial()) == nil {
+			m.from.Inventory().AddItem(m.item)
+		}
+		if invIt := m.from.Inventory().Item(m.item.ID(), m.item.Serial()); invIt != nil {
+			invIt.Loot = m.loot
+			invIt.Price = m.price
+		}
+	}
+}
+
+// PendingOps returns all item operations waiting for
+// the server confirmation.
+func (g *Game) PendingOps() []*PendingOp {
+	g.pendingMutex.Lock()
+	defer g.pendingMutex.Unlock()
+	ops := make([]*PendingOp, len(g.pendingOps))
+	copy(ops, g.pendingOps)
+	return ops
+}
+
+// ItemPending checks if specified item is a part of
+// any item operation waiting for the server confirmation.
+func (g *Game) ItemPending(it item.Item) bool {
+	g.pendingMutex.Lock()
+	defer g.pendingMutex.Unlock()
+	for _, op := range g.pendingOps {
+		for _, m := range op.moves {
+			if m.item.ID() == it.ID() && m.item.Serial() == it.Serial() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetOnPendingRollbackFunc sets function triggered after
+// pending item operation was rolled back.
+// The function is never triggered during the game state update.
+func (g *Game) SetOnPendingRollbackFunc(f func(op *PendingOp)) {
+	g.onPendingRollback = f
+}
+
+// addPendingOp registers new pending operation with specified
+// kind and item moves and returns it.
+// Specified confirm function is triggered after the server
+// confirms the operation, it could be nil.
+func (g *Game) addPendingOp(kind PendingKind, confirm func(), moves ...itemMove) *PendingOp {
+	g.pendingMutex.Lock()
+	defer g.pendingMutex.Unlock()
+	g.nextPendingID++
+	op := PendingOp{
+		id:      g.nextPendingID,
+		kind:    kind,
+		time:    time.Now(),
+		moves:   moves,
+		confirm: confirm,
+	}
+	g.pendingOps = append(g.pendingOps, &op)
+	return &op
+}
+
+// confirmPendingOps removes operations confirmed by the current
+// game state and applies again the ones that are still waiting
+// for the server, so the state update will not revert them.
+// Update mutex must be locked by the caller, confirm functions of
+// the returned operations should be triggered after unlocking.
+// Returns confirmed operations.
+func (g *Game) confirmPendingOps() []*PendingOp {
+	g.pendingMutex.Lock()
+	defer g.pendingMutex.Unlock()
+	confirmed := make([]*PendingOp, 0)
+	ops := g.pendingOps[:0]
+	for _, op := range g.pendingOps {
+		if op.confirmed() {
+			op.done = true
+			confirmed = append(confirmed, op)
+			continue
+		}
+		op.apply()
+		ops = append(ops, op)
+	}
+	g.pendingOps = ops
+	return confirmed
+}
+
+// rejectPendingOp removes the pending operation which request
+// failed with specified server error message and moves its items
+// back to the source containers.
+// Operation is matched by the request kind and the items named in
+// the error message. Error without item names matches only the
+// operation that is the only pending operation of its kind, other
+// operations are left to be rolled back after the pending timeout.
+// Update mutex must be locked by the caller, rollback function
+// should be triggered for the returned operation after unlocking.
+// Returns rejected operation or nil if no operation was matched.
+func (g *Game) rejectPendingOp(errMsg string) *PendingOp {
+	kind, ok := errorKind(errMsg)
+	if !ok {
+		return nil
+	}
+	words := errorWords(errMsg)
+	g.pendingMutex.Lock()
+	candidates := make([]int, 0)
+	named := make([]int, 0)
+	for i, op := range g.pendingOps {
+		if op.kind != kind {
+			continue
+		}
+		candidates = append(candidates, i)
+		if op.named(words) {
+			named = append(named, i)
+		}
+	}
+	if len(named) < 1 {
+		named = candidates
+	}
+	if len(named) != 1 {
+		g.pendingMutex.Unlock()
+		if len(named) > 1 {
+			log.Err.Printf("Game: unable to match server error with pending operation: %s",
+				errMsg)
+		}
+		return nil
+	}
+	op := g.pendingOps[named[0]]
+	g.pendingOps = append(g.pendingOps[:named[0]], g.pendingOps[named[0]+1:]...)
+	op.done = true
+	g.pendingMutex.Unlock()
+	op.rollback()
+	return op
+}
+
+// cancelPendingOp removes specified operation from pending
+// operations and rolls it back.
+// Operation already confirmed or rolled back is ignored.
+func (g *Game) cancelPendingOp(op *PendingOp) {
+	g.pendingMutex.Lock()
+	if op.done {
+		g.pendingMutex.Unlock()
+		return
+	}
+	for i, o := range g.pendingOps {
+		if o == op {
+			g.pendingOps = append(g.pendingOps[:i], g.pendingOps[i+1:]...)
+			break
+		}
+	}
+	op.done = true
+	g.pendingMutex.Unlock()
+	op.rollback()
+	g.pendingRolledBack(op)
+}
+
+// expirePendingOps removes all pending operations without
+// server confirmation for longer than the pending timeout
+// and moves their items back to the source containers.
+// Update mutex must be locked by the caller, rollback function
+// should be triggered for the returned operations after unlocking.
+// Returns expired operations.
+func (g *Game) expirePendingOps() []*PendingOp {
+	g.pendingMutex.Lock()
+	defer g.pendingMutex.Unlock()
+	expired := make([]*PendingOp, 0)
+	ops := g.pendingOps[:0]
+	for _, op := range g.pendingOps {
+		if time.Since(op.time) < pendingTimeout {
+			ops = append(ops, op)
+			continue
+		}
+		log.Err.Printf("Game: pending operation timed out: %d", op.id)
+		op.done = true
+		op.rollback()
+		expired = append(expired, op)
+	}
+	g.pendingOps = ops
+	return expired
+}
+
+// pendingRolledBack triggers the rollback function for specified
+// operation.
+// Operation must be removed from pending operations and marked as
+// done under the pending mutex, so it's never rolled back twice or
+// after confirmation.
+func (g *Game) pendingRolledBack(op *PendingOp) {
+	if g.onPendingRollback != nil {
+		g.onPendingRollback(op)
+	}
+}
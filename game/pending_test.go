@@ -0,0 +1,176 @@
+/*
+ * pending_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/item"
+)
+
+// TestPendingErrorKind tests matching server errors with
+// kinds of pending operations.
+func TestPendingErrorKind(t *testing.T) {
+	tests := []struct {
+		err  string
+		kind PendingKind
+		ok   bool
+	}{
+		{"Unable to handle trade request: unable to transfer items", PendingTrade, true},
+		{"unable to handle throw items request: item not found", PendingThrow, true},
+		{"Unable to handle transfer items request: object not found", PendingTransfer, true},
+		{"Unable to handle chat request: trade", 0, false},
+		{"transfer failed", 0, false},
+	}
+	for _, test := range tests {
+		kind, ok := errorKind(test.err)
+		if ok != test.ok || kind != test.kind {
+			t.Errorf("Invalid error kind: '%s': %d %v != %d %v", test.err,
+				kind, ok, test.kind, test.ok)
+		}
+	}
+}
+
+// TestPendingOpRollback tests rolling back pending operations
+// only once and never after confirmation.
+func TestPendingOpRollback(t *testing.T) {
+	game := new(Game)
+	rollbacks := 0
+	game.SetOnPendingRollbackFunc(func(op *PendingOp) {
+		rollbacks++
+	})
+	// Test.
+	op := game.addPendingOp(PendingTrade, nil)
+	game.cancelPendingOp(op)
+	game.cancelPendingOp(op)
+	if rollbacks != 1 {
+		t.Errorf("Invalid number of rollbacks: %d != 1", rollbacks)
+	}
+	op = game.addPendingOp(PendingTrade, nil)
+	game.confirmPendingOps()
+	game.cancelPendingOp(op)
+	if game.rejectPendingOp("Unable to handle trade request: test") != nil || rollbacks != 1 {
+		t.Errorf("Confirmed operation rolled back")
+	}
+	op = game.addPendingOp(PendingThrow, nil)
+	if game.rejectPendingOp("Unable to handle trade request: test") != nil {
+		t.Errorf("Operation rejected by error of different kind")
+	}
+	if game.rejectPendingOp("Unable to handle throw items request: test") != op {
+		t.Errorf("Operation not rejected")
+	}
+	game.cancelPendingOp(op)
+	if rollbacks != 1 {
+		t.Errorf("Invalid number of rollbacks: %d != 1", rollbacks)
+	}
+}
+
+// TestPendingOpItems tests applying, confirming and rolling back
+// item moves of pending operation.
+func TestPendingOpItems(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(res.ModuleData{}))
+	from := NewCharacter(character.New(res.CharacterData{ID: "from", Level: 1}), game)
+	to := NewCharacter(character.New(res.CharacterData{ID: "to", Level: 1}), game)
+	it := item.NewWeapon(res.WeaponData{ID: "weapon"})
+	from.Inventory().AddItem(it)
+	invIt := from.Inventory().Item(it.ID(), it.Serial())
+	if invIt == nil {
+		t.Fatalf("Item not added to inventory")
+	}
+	invIt.Loot = true
+	invIt.Price = 10
+	// Test.
+	op := game.addPendingOp(PendingTransfer, nil, newItemMove(it, from, to))
+	if op.confirmed() {
+		t.Errorf("Operation confirmed before items were moved")
+	}
+	op.apply()
+	op.apply()
+	if from.Inventory().Item(it.ID(), it.Serial()) != nil ||
+		len(to.Inventory().Items()) != 1 {
+		t.Errorf("Items not moved on apply")
+	}
+	if !op.confirmed() {
+		t.Errorf("Operation not confirmed after items were moved")
+	}
+	op.rollback()
+	if to.Inventory().Item(it.ID(), it.Serial()) != nil {
+		t.Errorf("Item not removed from destination on rollback")
+	}
+	invIt = from.Inventory().Item(it.ID(), it.Serial())
+	if invIt == nil {
+		t.Fatalf("Item not moved back on rollback")
+	}
+	if !invIt.Loot || invIt.Price != 10 {
+		t.Errorf("Invalid item state after rollback: %v %d != true 10",
+			invIt.Loot, invIt.Price)
+	}
+	if op.confirmed() {
+		t.Errorf("Operation confirmed after rollback")
+	}
+}
+
+// TestPendingOpReject tests matching server errors with pending
+// operations by the items named in the error.
+func TestPendingOpReject(t *testing.T) {
+	// Create game.
+	game := New(flame.NewModule(res.ModuleData{}))
+	seller := NewCharacter(character.New(res.CharacterData{ID: "seller", Level: 1}), game)
+	buyer := NewCharacter(character.New(res.CharacterData{ID: "buyer", Level: 1}), game)
+	items := []item.Item{
+		item.NewWeapon(res.WeaponData{ID: "weapon"}),
+		item.NewWeapon(res.WeaponData{ID: "weapon"}),
+	}
+	ops := make([]*PendingOp, 0)
+	for _, it := range items {
+		seller.Inventory().AddItem(it)
+		move := newItemMove(it, seller, buyer)
+		seller.Inventory().RemoveItem(it)
+		buyer.Inventory().AddItem(it)
+		ops = append(ops, game.addPendingOp(PendingTrade, nil, move))
+	}
+	// Test.
+	if game.rejectPendingOp("Unable to handle trade request: test") != nil {
+		t.Errorf("Operation rejected by ambiguous error")
+	}
+	errMsg := fmt.Sprintf("Unable to handle trade request: item not found: %s#%s",
+		items[1].ID(), items[1].Serial())
+	if op := game.rejectPendingOp(errMsg); op != ops[1] {
+		t.Fatalf("Invalid rejected operation: %v != %v", op, ops[1])
+	}
+	if seller.Inventory().Item(items[1].ID(), items[1].Serial()) == nil {
+		t.Errorf("Items of rejected operation not moved back")
+	}
+	if seller.Inventory().Item(items[0].ID(), items[0].Serial()) != nil {
+		t.Errorf("Items of other operation moved back")
+	}
+	if op := game.rejectPendingOp("Unable to handle trade request: test"); op != ops[0] {
+		t.Errorf("Last pending operation not rejected: %v != %v", op, ops[0])
+	}
+}
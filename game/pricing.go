@@ -0,0 +1,181 @@
+/*
+ * pricing.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"math"
+
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/flag"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/mural/data/res"
+)
+
+var (
+	// Attitudes for attitude names used in the merchant data.
+	merchantAttitudes = map[string]character.Attitude{
+		"friendly": character.Friendly,
+		"neutral":  character.Neutral,
+		"hostile":  character.Hostile,
+	}
+)
+
+// Struct for trade prices of merchant items.
+type Pricing struct {
+	merchant item.Container
+	buyMult  float64
+	sellMult float64
+	discount float64
+	refuse   map[string]bool
+	buyback  map[string]int
+}
+
+// Pricing returns pricing for trade between specified
+// merchant and customer.
+// Multipliers, discounts and refused items are taken from
+// the merchant data, if there is no data for the merchant
+// items are traded for their value.
+// Items from the merchant buyback list are priced for the
+// price they were sold.
+// With the game server merchant data is not used and items
+// are traded for their value, since the server doesn't know
+// the GUI data and doesn't check the prices.
+func (g *Game) Pricing(merchant, customer item.Container) *Pricing {
+	p := Pricing{
+		merchant: merchant,
+		buyMult:  1,
+		sellMult: 1,
+		refuse:   make(map[string]bool),
+		buyback:  make(map[string]int),
+	}
+	if g.Server() != nil {
+		return &p
+	}
+	for _, b := range g.Buyback(merchant) {
+		p.buyback[b.Item.ID()+b.Item.Serial()] = b.Price
+	}
+	data := res.Merchant(merchant.ID())
+	if data == nil {
+		return &p
+	}
+	if data.BuyMultiplier > 0 {
+		p.buyMult = data.BuyMultiplier
+	}
+	if data.SellMultiplier > 0 {
+		p.sellMult = data.SellMultiplier
+	}
+	for _, id := range data.Refuse {
+		p.refuse[id] = true
+	}
+	merchantChar := g.Char(merchant.ID(), merchant.Serial())
+	customerChar := g.Char(customer.ID(), customer.Serial())
+	if merchantChar == nil || customerChar == nil {
+		return &p
+	}
+	for _, d := range data.Discounts {
+		if len(d.Attitude) > 0 &&
+			merchantChar.AttitudeFor(customerChar) != merchantAttitude(d.Attitude) {
+			continue
+		}
+		if len(d.Flag) > 0 && !customerChar.HasFlag(flag.Flag(d.Flag)) {
+			continue
+		}
+		p.discount += d.Discount
+	}
+	p.discount = math.Max(0, math.Min(p.discount, 1))
+	return &p
+}
+
+// BuyPrice returns price of specified merchant item for
+// the customer.
+// Item price set in the merchant inventory is used as the
+// base price, if there is no such item in the inventory the
+// item value is used instead.
+func (p *Pricing) BuyPrice(it item.Item) int {
+	if price, ok := p.buyback[it.ID()+it.Serial()]; ok {
+		return price
+	}
+	base := it.Value()
+	if invIt := p.merchant.Inventory().Item(it.ID(), it.Serial()); invIt != nil {
+		base = invIt.Price
+	}
+	return price(float64(base) * p.buyMult * (1 - p.discount))
+}
+
+// SellPrice returns price that the merchant pays for
+// specified customer item.
+// Returns 0 if the merchant refuses to buy the item.
+func (p *Pricing) SellPrice(it item.Item) int {
+	if !p.Accepts(it) {
+		return 0
+	}
+	return price(float64(it.Value()) * p.sellMult)
+}
+
+// Accepts checks if the merchant accepts specified item.
+func (p *Pricing) Accepts(it item.Item) bool {
+	return !p.refuse[it.ID()]
+}
+
+// Value returns trade value for the customer, i.e. difference
+// between the price of items to sell and items to buy.
+func (p *Pricing) Value(sell, buy []item.Item) (v int) {
+	for _, it := range sell {
+		v += p.SellPrice(it)
+	}
+	for _, it := range buy {
+		v -= p.BuyPrice(it)
+	}
+	return
+}
+
+// Fair checks if the merchant accepts all items to sell and
+// the price of items to sell is greater or equal to the price
+// of items to buy.
+func (p *Pricing) Fair(sell, buy []item.Item) bool {
+	for _, it := range sell {
+		if !p.Accepts(it) {
+			return false
+		}
+	}
+	return p.Value(sell, buy) >= 0
+}
+
+// merchantAttitude returns attitude for specified attitude name
+// from the merchant data.
+// Names not listed in merchant attitudes are used as attitude
+// values, e.g. attFriendly.
+func merchantAttitude(name string) character.Attitude {
+	if att, ok := merchantAttitudes[name]; ok {
+		return att
+	}
+	return character.Attitude(name)
+}
+
+// price rounds specified price to the nearest non-negative
+// integer.
+func price(v float64) int {
+	return int(math.Max(0, math.Round(v)))
+}
@@ -0,0 +1,152 @@
+/*
+ * pricing_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	flameres "github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/mural/data/res"
+)
+
+// Struct for test item with value.
+type testItem struct {
+	item.Item
+	id    string
+	value int
+}
+
+// ID returns ID of the test item.
+func (it testItem) ID() string {
+	return it.id
+}
+
+// Serial returns serial of the test item.
+func (it testItem) Serial() string {
+	return "0"
+}
+
+// Value returns value of the test item.
+func (it testItem) Value() int {
+	return it.value
+}
+
+// TestPricing tests trade prices for different merchant data.
+func TestPricing(t *testing.T) {
+	defer func() { res.Merchants = nil }()
+	// Create game.
+	mod := flame.NewModule(flameres.ModuleData{})
+	mod.Chapter().AddAreas(area.New(flameres.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	game := New(mod)
+	// Create characters.
+	merchantData := flameres.CharacterData{ID: "merchant", Level: 1,
+		Attitude: string(character.Friendly)}
+	merchant := NewCharacter(character.New(merchantData), game)
+	customer := NewCharacter(character.New(flameres.CharacterData{ID: "customer", Level: 1}), game)
+	for _, c := range []*Character{merchant, customer} {
+		err := game.SpawnChar(c)
+		if err != nil {
+			t.Fatalf("Unable to spawn character: %v", err)
+		}
+	}
+	sword := testItem{id: "sword", value: 100}
+	rubbish := testItem{id: "rubbish", value: 10}
+	// Test.
+	tests := []struct {
+		data         res.MerchantData
+		buyPrice     int
+		sellPrice    int
+		rubbishPrice int
+		fair         bool
+	}{
+		// No merchant data.
+		{res.MerchantData{ID: "other"}, 100, 100, 10, true},
+		// Multipliers.
+		{res.MerchantData{ID: "merchant", BuyMultiplier: 1.5, SellMultiplier: 0.5},
+			150, 50, 5, false},
+		// Discount for attitude.
+		{res.MerchantData{ID: "merchant", Discounts: []res.MerchantDiscount{
+			{Attitude: "friendly", Discount: 0.2},
+			{Attitude: "hostile", Discount: 0.5},
+		}}, 80, 100, 10, true},
+		// Discount clamp.
+		{res.MerchantData{ID: "merchant", Discounts: []res.MerchantDiscount{
+			{Attitude: "friendly", Discount: 0.6},
+			{Attitude: string(character.Friendly), Discount: 0.6},
+		}}, 0, 100, 10, true},
+		// Refused item.
+		{res.MerchantData{ID: "merchant", Refuse: []string{"rubbish"}},
+			100, 100, 0, false},
+	}
+	for i, test := range tests {
+		res.Merchants = []res.MerchantData{test.data}
+		pricing := game.Pricing(merchant, customer)
+		if price := pricing.BuyPrice(sword); price != test.buyPrice {
+			t.Errorf("Test %d: invalid buy price: %d != %d", i, price,
+				test.buyPrice)
+		}
+		if price := pricing.SellPrice(sword); price != test.sellPrice {
+			t.Errorf("Test %d: invalid sell price: %d != %d", i, price,
+				test.sellPrice)
+		}
+		if price := pricing.SellPrice(rubbish); price != test.rubbishPrice {
+			t.Errorf("Test %d: invalid rubbish sell price: %d != %d", i, price,
+				test.rubbishPrice)
+		}
+		sell := []item.Item{sword, rubbish}
+		buy := []item.Item{sword}
+		if fair := pricing.Fair(sell, buy); fair != test.fair {
+			t.Errorf("Test %d: invalid fair trade: %v != %v", i, fair,
+				test.fair)
+		}
+	}
+}
+
+// TestPricingServer tests ignoring merchant data in games
+// on the server.
+func TestPricingServer(t *testing.T) {
+	defer func() { res.Merchants = nil }()
+	res.Merchants = []res.MerchantData{{ID: "merchant", BuyMultiplier: 1.5,
+		SellMultiplier: 0.5, Refuse: []string{"sword"}}}
+	// Create game.
+	game, _ := newLoopbackGame(t)
+	defer game.Server().Close()
+	merchant := NewCharacter(character.New(flameres.CharacterData{ID: "merchant", Level: 1}), game)
+	customer := NewCharacter(character.New(flameres.CharacterData{ID: "customer", Level: 1}), game)
+	sword := testItem{id: "sword", value: 100}
+	// Test.
+	pricing := game.Pricing(merchant, customer)
+	if price := pricing.BuyPrice(sword); price != sword.value {
+		t.Errorf("Invalid buy price: %d != %d", price, sword.value)
+	}
+	if price := pricing.SellPrice(sword); price != sword.value {
+		t.Errorf("Invalid sell price: %d != %d", price, sword.value)
+	}
+}
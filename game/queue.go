@@ -0,0 +1,74 @@
+/*
+ * queue.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+package game
+
+import (
+	"github.com/isangeles/fire/request"
+)
+
+// mergeable checks if specified request can be merged with
+// other requests.
+// Requests with single value fields like pause, load or close
+// can't be merged.
+func mergeable(req request.Request) bool {
+	return !req.Pause && len(req.Load) < 1 && req.Close == 0
+}
+
+// emptyRequest checks if specified request is empty.
+func emptyRequest(req request.Request) bool {
+	return mergeable(req) && requestSize(req) < 1
+}
+
+// requestSize returns number of sub-requests in specified
+// request.
+func requestSize(req request.Request) int {
+	return len(req.Login) + len(req.NewChar) + len(req.Move) + len(req.SetPos) +
+		len(req.Chat) + len(req.Target) + len(req.Training) + len(req.Use) +
+		len(req.Equip) + len(req.Unequip) + len(req.ThrowItems) +
+		len(req.TransferItems) + len(req.Trade) + len(req.Dialog) +
+		len(req.DialogEnd) + len(req.DialogAnswer) + len(req.Command) +
+		len(req.Save)
+}
+
+// mergeRequests appends all sub-requests from the source request
+// to the destination request.
+func mergeRequests(dst *request.Request, src request.Request) {
+	dst.Login = append(dst.Login, src.Login...)
+	dst.NewChar = append(dst.NewChar, src.NewChar...)
+	dst.Move = append(dst.Move, src.Move...)
+	dst.SetPos = append(dst.SetPos, src.SetPos...)
+	dst.Chat = append(dst.Chat, src.Chat...)
+	dst.Target = append(dst.Target, src.Target...)
+	dst.Training = append(dst.Training, src.Training...)
+	dst.Use = append(dst.Use, src.Use...)
+	dst.Equip = append(dst.Equip, src.Equip...)
+	dst.Unequip = append(dst.Unequip, src.Unequip...)
+	dst.ThrowItems = append(dst.ThrowItems, src.ThrowItems...)
+	dst.TransferItems = append(dst.TransferItems, src.TransferItems...)
+	dst.Trade = append(dst.Trade, src.Trade...)
+	dst.Dialog = append(dst.Dialog, src.Dialog...)
+	dst.DialogEnd = append(dst.DialogEnd, src.DialogEnd...)
+	dst.DialogAnswer = append(dst.DialogAnswer, src.DialogAnswer...)
+	dst.Command = append(dst.Command, src.Command...)
+	dst.Save = append(dst.Save, src.Save...)
+}
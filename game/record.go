@@ -0,0 +1,217 @@
+/*
+ * record.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+const (
+	SessionFileExt = ".session"
+)
+
+var errReplayClosed = errors.New("Replay closed")
+
+// Struct for session file entry.
+// Time is the number of milliseconds since the start
+// of the recording.
+type sessionEntry struct {
+	Time     int64              `json:"time"`
+	Request  *request.Request   `json:"request,omitempty"`
+	Response *response.Response `json:"response,omitempty"`
+}
+
+// Struct for recorder of the server session.
+// Recorder writes all requests and responses to the session
+// file, one JSON entry per line.
+type Recorder struct {
+	file   *os.File
+	writer *bufio.Writer
+	mutex  sync.Mutex
+	start  time.Time
+}
+
+// NewRecorder creates new recorder with session file
+// under specified path.
+func NewRecorder(path string) (*Recorder, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create session directory: %v", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create session file: %v", err)
+	}
+	r := Recorder{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		start:  time.Now(),
+	}
+	return &r, nil
+}
+
+// NewSessionRecorder creates new recorder with session file
+// inside specified directory, named after the current time.
+func NewSessionRecorder(dir string) (*Recorder, error) {
+	name := time.Now().Format("20060102150405") + SessionFileExt
+	return NewRecorder(filepath.Join(dir, name))
+}
+
+// RecordRequest writes specified request to the session file.
+func (r *Recorder) RecordRequest(req request.Request) error {
+	return r.write(sessionEntry{Request: &req})
+}
+
+// RecordResponse writes specified response to the session file.
+func (r *Recorder) RecordResponse(resp response.Response) error {
+	return r.write(sessionEntry{Response: &resp})
+}
+
+// Close closes the session file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	err := r.writer.Flush()
+	if err != nil {
+		r.file.Close()
+		return fmt.Errorf("Unable to write session file: %v", err)
+	}
+	return r.file.Close()
+}
+
+// write writes specified entry to the session file.
+func (r *Recorder) write(entry sessionEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry.Time = time.Since(r.start).Milliseconds()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal session entry: %v", err)
+	}
+	r.writer.Write(data)
+	r.writer.WriteByte('\n')
+	return r.writer.Flush()
+}
+
+// Struct for replay of the recorded server session.
+// Replay is a server transport that returns recorded
+// responses with the original pacing, divided by the
+// replay speed.
+// All requests sent to the replay are dropped.
+type Replay struct {
+	path      string
+	speed     float64
+	entries   []sessionEntry
+	next      int
+	last      int64
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReplay creates new replay of the session file under
+// specified path.
+// Speed lower or equal to 0 disables pacing, so all recorded
+// responses are returned right away.
+func NewReplay(path string, speed float64) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open session file: %v", err)
+	}
+	defer file.Close()
+	r := Replay{
+		path:   path,
+		speed:  speed,
+		closed: make(chan struct{}),
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry sessionEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to unmarshal session entry: %v", err)
+		}
+		if entry.Response == nil {
+			continue
+		}
+		r.entries = append(r.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Unable to read session file: %v", err)
+	}
+	return &r, nil
+}
+
+// Send drops specified request.
+func (r *Replay) Send(req request.Request) error {
+	select {
+	case <-r.closed:
+		return errReplayClosed
+	default:
+		return nil
+	}
+}
+
+// Receive returns next recorded response.
+// After the last response it blocks until the replay
+// is closed.
+func (r *Replay) Receive() (response.Response, error) {
+	if r.next >= len(r.entries) {
+		<-r.closed
+		return response.Response{}, errReplayClosed
+	}
+	entry := r.entries[r.next]
+	r.next++
+	if r.speed > 0 {
+		delay := time.Duration(float64(entry.Time-r.last)/r.speed) * time.Millisecond
+		select {
+		case <-r.closed:
+			return response.Response{}, errReplayClosed
+		case <-time.After(delay):
+		}
+	}
+	r.last = entry.Time
+	return *entry.Response, nil
+}
+
+// Close closes the replay.
+func (r *Replay) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return nil
+}
+
+// Address returns path to the replayed session file.
+func (r *Replay) Address() string {
+	return "replay:" + r.path
+}
@@ -0,0 +1,121 @@
+/*
+ * record_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// TestRecordReplay tests recording the server session
+// and replaying recorded responses.
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test"+SessionFileExt)
+	// Record.
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("Unable to create recorder: %v", err)
+	}
+	recorder.RecordRequest(request.Request{Command: []string{"test"}})
+	recorder.RecordResponse(response.Response{Error: []string{"err1"}})
+	recorder.RecordResponse(response.Response{Error: []string{"err2"}})
+	err = recorder.Close()
+	if err != nil {
+		t.Fatalf("Unable to close recorder: %v", err)
+	}
+	// Replay.
+	replay, err := NewReplay(path, 0)
+	if err != nil {
+		t.Fatalf("Unable to create replay: %v", err)
+	}
+	for _, e := range []string{"err1", "err2"} {
+		resp, err := replay.Receive()
+		if err != nil {
+			t.Fatalf("Unable to receive replayed response: %v", err)
+		}
+		if len(resp.Error) != 1 || resp.Error[0] != e {
+			t.Errorf("Invalid replayed response: %v != %s", resp.Error, e)
+		}
+	}
+	replay.Close()
+	_, err = replay.Receive()
+	if err == nil {
+		t.Errorf("No error after replay end")
+	}
+}
+
+// TestServerRecorder tests recording responses received
+// right after the connection to the server is opened.
+func TestServerRecorder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test"+SessionFileExt)
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("Unable to create recorder: %v", err)
+	}
+	recorder.RecordResponse(response.Response{Error: []string{"err1"}})
+	recorder.Close()
+	// Test.
+	recordPath := filepath.Join(dir, "record"+SessionFileExt)
+	recorder, err = NewRecorder(recordPath)
+	if err != nil {
+		t.Fatalf("Unable to create recorder: %v", err)
+	}
+	server, err := newServer(func() (Transport, error) {
+		return NewReplay(path, 0)
+	}, recorder)
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	for i := 0; i < 100 && fileSize(recordPath) < 1; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	server.Close()
+	replay, err := NewReplay(recordPath, 0)
+	if err != nil {
+		t.Fatalf("Unable to create replay: %v", err)
+	}
+	defer replay.Close()
+	resp, err := replay.Receive()
+	if err != nil {
+		t.Fatalf("Unable to receive recorded response: %v", err)
+	}
+	if len(resp.Error) != 1 || resp.Error[0] != "err1" {
+		t.Errorf("Invalid recorded response: %v", resp.Error)
+	}
+}
+
+// fileSize returns size of the file under specified path.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
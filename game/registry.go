@@ -0,0 +1,164 @@
+/*
+ * registry.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"sync"
+)
+
+// Struct for registry of game characters.
+// Registry is safe for concurrent use.
+type charRegistry struct {
+	mutex   sync.RWMutex
+	chars   map[string]*Character
+	players []*Character
+	active  *Character
+}
+
+// newCharRegistry creates new empty character registry.
+func newCharRegistry() *charRegistry {
+	cr := charRegistry{chars: make(map[string]*Character)}
+	return &cr
+}
+
+// char returns character with specified ID and serial.
+func (cr *charRegistry) char(id, serial string) *Character {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+	return cr.chars[id+serial]
+}
+
+// addChar adds specified character to the registry, if
+// there is no character with the same ID and serial yet.
+// Returns character from the registry.
+func (cr *charRegistry) addChar(char *Character) *Character {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	if c := cr.chars[char.ID()+char.Serial()]; c != nil {
+		return c
+	}
+	cr.chars[char.ID()+char.Serial()] = char
+	return char
+}
+
+// removeChar removes specified character from the registry.
+// Player characters are never removed.
+// Returns false if the character was not removed.
+func (cr *charRegistry) removeChar(char *Character) bool {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	for _, p := range cr.players {
+		if p == char {
+			return false
+		}
+	}
+	if cr.chars[char.ID()+char.Serial()] != char {
+		return false
+	}
+	delete(cr.chars, char.ID()+char.Serial())
+	return true
+}
+
+// size returns number of characters in the registry.
+func (cr *charRegistry) size() int {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+	return len(cr.chars)
+}
+
+// addPlayer adds specified character to the registry as player
+// character.
+// Returns false if the character already was a player character.
+func (cr *charRegistry) addPlayer(char *Character) bool {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	for _, p := range cr.players {
+		if p.ID() == char.ID() && p.Serial() == char.Serial() {
+			return false
+		}
+	}
+	cr.players = append(cr.players, char)
+	cr.chars[char.ID()+char.Serial()] = char
+	return true
+}
+
+// playerChars returns copy of the player characters list.
+func (cr *charRegistry) playerChars() []*Character {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+	players := make([]*Character, len(cr.players))
+	copy(players, cr.players)
+	return players
+}
+
+// activeChar returns active player character.
+func (cr *charRegistry) activeChar() *Character {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+	return cr.active
+}
+
+// setActiveChar sets specified character as active player character.
+func (cr *charRegistry) setActiveChar(char *Character) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.active = char
+}
+
+// rangeChars calls specified function for each character in the
+// registry, until the function returns false.
+// The function is called on the snapshot of the registry, so
+// it's safe to modify the registry inside the function.
+func (cr *charRegistry) rangeChars(f func(c *Character) bool) {
+	cr.mutex.RLock()
+	chars := make([]*Character, 0, len(cr.chars))
+	for _, c := range cr.chars {
+		chars = append(chars, c)
+	}
+	cr.mutex.RUnlock()
+	for _, c := range chars {
+		if !f(c) {
+			return
+		}
+	}
+}
+
+// reset removes all non-player characters from the registry.
+// Returns all removed characters.
+func (cr *charRegistry) reset() []*Character {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	removed := make([]*Character, 0)
+	players := make(map[string]*Character)
+	for _, p := range cr.players {
+		players[p.ID()+p.Serial()] = p
+	}
+	for k, c := range cr.chars {
+		if players[k] != c {
+			removed = append(removed, c)
+		}
+	}
+	cr.chars = players
+	return removed
+}
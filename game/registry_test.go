@@ -0,0 +1,141 @@
+/*
+ * registry_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+
+	"github.com/isangeles/fire/response"
+)
+
+// TestCharRegistryConcurrent tests concurrent access to the game
+// characters from the update loop and response handlers.
+// Should be run with the race detector enabled.
+func TestCharRegistryConcurrent(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	// Create characters.
+	chars := make([]*Character, 0)
+	for i := 0; i < 20; i++ {
+		charData := res.CharacterData{ID: fmt.Sprintf("char%d", i), Level: 1}
+		chars = append(chars, NewCharacter(character.New(charData), game))
+	}
+	// Test.
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for _, c := range chars {
+			game.chars.addChar(c)
+			game.updateMovement(time.Now())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for _, c := range chars {
+			game.chars.addChar(c)
+			game.handleCharacterResponse(response.Character{ID: c.ID(), Serial: c.Serial()})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for _, c := range chars {
+			game.Char(c.ID(), c.Serial())
+			game.PlayerChars()
+			game.ActivePlayerChar()
+			game.charPositions()
+		}
+	}()
+	wg.Wait()
+	if len(game.PlayerChars()) != len(chars) {
+		t.Errorf("Invalid number of player characters: %d != %d",
+			len(game.PlayerChars()), len(chars))
+	}
+	count := 0
+	game.RangeChars(func(c *Character) bool {
+		count++
+		return true
+	})
+	if count != len(chars) {
+		t.Errorf("Invalid number of game characters: %d != %d",
+			count, len(chars))
+	}
+}
+
+// TestGameStepConcurrent tests game update steps running
+// concurrently with handling of the server responses.
+// Should be run with the race detector enabled.
+func TestGameStepConcurrent(t *testing.T) {
+	// Create game.
+	mod := flame.NewModule(res.ModuleData{})
+	game := New(mod)
+	// Create characters.
+	chars := make([]*Character, 0)
+	for i := 0; i < 20; i++ {
+		charData := res.CharacterData{ID: fmt.Sprintf("char%d", i), Level: 1}
+		chars = append(chars, NewCharacter(character.New(charData), game))
+	}
+	for _, c := range chars[:10] {
+		game.AddPlayerChar(c)
+	}
+	update := response.Update{Module: mod.Data()}
+	update.Module.Config = map[string][]string{"id": {mod.Conf().ID}}
+	// Test.
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			game.Step(stepTime.Milliseconds())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			game.handleUpdateResponse(update)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for _, c := range chars[10:] {
+			game.chars.addChar(c)
+			game.handleResponse(response.Response{
+				Character: []response.Character{{ID: c.ID(), Serial: c.Serial()}},
+			})
+		}
+	}()
+	wg.Wait()
+	if len(game.PlayerChars()) != len(chars) {
+		t.Errorf("Invalid number of player characters: %d != %d",
+			len(game.PlayerChars()), len(chars))
+	}
+}
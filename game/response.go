@@ -1,7 +1,7 @@
 /*
  * response.go
  *
- * Copyright 2020-2024 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -25,6 +25,7 @@ package game
 
 import (
 	"sync"
+	"time"
 
 	flameres "github.com/isangeles/flame/data/res"
 	"github.com/isangeles/flame/objects"
@@ -38,17 +39,24 @@ import (
 )
 
 var (
-	addPlayerMutex sync.Mutex
-	updateMutex    sync.Mutex
+	updateMutex sync.Mutex
 )
 
 // handleResponse handles specified response from Fire server.
 func (g *Game) handleResponse(resp response.Response) {
-	g.handleUpdateResponse(resp.Update)
-	if g.pause != resp.Paused {
-		g.pause = resp.Paused
-		log.Inf.Print(pauseMessage(g.pause))
+	changed, confirmed := g.handleUpdateResponse(resp.Update)
+	if changed {
+		if g.onChapterChange != nil {
+			g.onChapterChange(g.Chapter())
+		}
+		g.events.Emit(ChapterChangedEvent{g.Chapter()})
+	}
+	for _, op := range confirmed {
+		if op.confirm != nil {
+			op.confirm()
+		}
 	}
+	g.setPause(resp.Paused)
 	for _, r := range resp.Character {
 		g.handleCharacterResponse(r)
 	}
@@ -63,40 +71,62 @@ func (g *Game) handleResponse(resp response.Response) {
 	}
 	for _, r := range resp.Error {
 		log.Err.Printf("Game server: error response: %s", r)
+		updateMutex.Lock()
+		op := g.rejectPendingOp(r)
+		updateMutex.Unlock()
+		if op != nil {
+			g.pendingRolledBack(op)
+		}
 	}
 }
 
 // handleUpdateResponse handles update response.
-func (g *Game) handleUpdateResponse(resp response.Update) {
+// Responses without the module state are ignored.
+// Returns true if the update changed current game chapter and
+// pending operations confirmed by the update.
+func (g *Game) handleUpdateResponse(resp response.Update) (bool, []*PendingOp) {
+	if !hasUpdate(resp) {
+		return false, nil
+	}
 	updateMutex.Lock()
 	defer updateMutex.Unlock()
+	chapterID := g.Chapter().Conf().ID
+	positions := g.charPositions()
 	flameres.Clear()
 	flameres.TranslationBases = res.TranslationBases
+	applyStart := time.Now()
 	g.Apply(resp.Module)
+	if g.Server() != nil {
+		g.Server().recordUpdate(time.Since(applyStart))
+	}
+	if g.Chapter().Conf().ID != chapterID {
+		log.Dbg.Printf("Game: chapter changed by the server: %s -> %s",
+			chapterID, g.Chapter().Conf().ID)
+		g.rebindChars()
+		return true, nil
+	}
+	g.correctPositions(positions, time.Now())
+	return false, g.confirmPendingOps()
 }
 
 // handleCharacterResponse handles new characters from server response.
 func (g *Game) handleCharacterResponse(resp response.Character) {
-	addPlayerMutex.Lock()
-	defer addPlayerMutex.Unlock()
-	for _, p := range g.PlayerChars() {
-		if p.ID() == resp.ID && p.Serial() == resp.Serial {
+	gameChar := g.Char(resp.ID, resp.Serial)
+	if gameChar == nil {
+		updateMutex.Lock()
+		char := g.Chapter().Character(resp.ID, resp.Serial)
+		updateMutex.Unlock()
+		if char == nil {
+			log.Err.Printf("Game: character from new char response not found in current module: %s %s",
+				resp.ID, resp.Serial)
 			return
 		}
+		gameChar = g.chars.addChar(NewCharacter(char, g))
 	}
-	gameChar := g.Char(resp.ID, resp.Serial)
-	if gameChar != nil {
-		g.AddPlayerChar(gameChar)
-		return
-	}
-	char := g.Chapter().Character(resp.ID, resp.Serial)
-	if char == nil {
-		log.Err.Printf("Game: character from new char response not found in current module: %s %s",
-			resp.ID, resp.Serial)
+	if !g.chars.addPlayer(gameChar) {
 		return
 	}
-	gameChar = NewCharacter(char, g)
-	g.AddPlayerChar(gameChar)
+	g.SetActivePlayerChar(gameChar)
 }
 
 // handleChatResponse handles chat response.
@@ -115,9 +145,6 @@ func (g *Game) handleUseResponse(resp response.Use) {
 	if char == nil {
 		return
 	}
-	if char.onUse == nil {
-		return
-	}
 	usable := char.Usable(resp.ObjectID, resp.ObjectSerial)
 	if usable == nil {
 		// Search for item or area object.
@@ -135,5 +162,13 @@ func (g *Game) handleUseResponse(resp response.Use) {
 		}
 		usable = u
 	}
-	char.onUse(usable)
+	char.used(usable)
+}
+
+// hasUpdate checks if specified update response carries
+// the game module state.
+// Responses without update, e.g. with errors or chat messages
+// only, contain empty module data.
+func hasUpdate(resp response.Update) bool {
+	return len(resp.Module.Config) > 0
 }
@@ -0,0 +1,179 @@
+/*
+ * response_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/fire/response"
+)
+
+// TestGameCharacterResponse tests adding player characters
+// from the server responses.
+func TestGameCharacterResponse(t *testing.T) {
+	// Create game.
+	game, loopback := newLoopbackGame(t)
+	defer game.Server().Close()
+	char := NewCharacter(character.New(res.CharacterData{ID: "char", Level: 1}), game)
+	err := game.SpawnChar(char)
+	if err != nil {
+		t.Fatalf("Unable to spawn character: %v", err)
+	}
+	// Test.
+	charResp := response.Character{ID: char.ID(), Serial: char.Serial()}
+	err = loopback.Respond(response.Response{Character: []response.Character{charResp}})
+	if err != nil {
+		t.Fatalf("Unable to queue character response: %v", err)
+	}
+	waitFor(t, "player character", func() bool {
+		pc := game.ActivePlayerChar()
+		return pc != nil && pc.ID() == char.ID() && pc.Serial() == char.Serial()
+	})
+	if len(game.PlayerChars()) != 1 {
+		t.Errorf("Invalid number of player characters: %d != 1",
+			len(game.PlayerChars()))
+	}
+}
+
+// TestGameTradeResponse tests sending trade request and handling
+// server confirmation and rejection of the trade.
+func TestGameTradeResponse(t *testing.T) {
+	// Create game.
+	game, loopback := newLoopbackGame(t)
+	defer game.Server().Close()
+	merchant := NewCharacter(character.New(res.CharacterData{ID: "merchant", Level: 1}), game)
+	customer := NewCharacter(character.New(res.CharacterData{ID: "customer", Level: 1}), game)
+	for _, c := range []*Character{merchant, customer} {
+		err := game.SpawnChar(c)
+		if err != nil {
+			t.Fatalf("Unable to spawn character: %v", err)
+		}
+	}
+	rollbacks := make(chan *PendingOp, 1)
+	game.SetOnPendingRollbackFunc(func(op *PendingOp) {
+		rollbacks <- op
+	})
+	trades := make(chan TradeCompletedEvent, 2)
+	SubscribeTo(game.Events(), func(e TradeCompletedEvent) {
+		trades <- e
+	})
+	it := item.NewWeapon(res.WeaponData{ID: "weapon"})
+	customer.Inventory().AddItem(it)
+	// Test request.
+	game.Trade(merchant, customer, []item.Item{it}, nil)
+	reqs := loopback.Requests()
+	if len(reqs) != 1 || len(reqs[0].Trade) != 1 {
+		t.Fatalf("Invalid requests received by transport: %v", reqs)
+	}
+	trade := reqs[0].Trade[0]
+	if trade.Buy.ObjectFromID != merchant.ID() || trade.Buy.ObjectToID != customer.ID() {
+		t.Errorf("Invalid trade request: %v", trade)
+	}
+	if len(game.Buyback(merchant)) > 0 {
+		t.Errorf("Buyback list changed before trade confirmation")
+	}
+	// Test rejection.
+	err := loopback.Respond(response.Response{Error: []string{"Unable to handle trade request: test"}})
+	if err != nil {
+		t.Fatalf("Unable to queue error response: %v", err)
+	}
+	select {
+	case op := <-rollbacks:
+		if op.Kind() != PendingTrade {
+			t.Errorf("Invalid rolled back operation: %d != %d", op.Kind(), PendingTrade)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Rejected trade not rolled back")
+	}
+	if len(trades) > 0 {
+		t.Errorf("Trade event emitted for rejected trade")
+	}
+	// Test confirmation.
+	game.Trade(merchant, customer, []item.Item{it}, nil)
+	if len(game.PendingOps()) != 1 {
+		t.Fatalf("Invalid number of pending operations: %d != 1",
+			len(game.PendingOps()))
+	}
+	update := game.Data()
+	update.Config = map[string][]string{"id": {game.Conf().ID}}
+	err = loopback.Respond(response.Response{Update: response.Update{Module: update}})
+	if err != nil {
+		t.Fatalf("Unable to queue update response: %v", err)
+	}
+	waitFor(t, "trade confirmation", func() bool {
+		return len(game.PendingOps()) == 0
+	})
+	if len(rollbacks) > 0 {
+		t.Errorf("Confirmed trade rolled back")
+	}
+	select {
+	case e := <-trades:
+		if e.Seller != item.Container(merchant) || e.Buyer != item.Container(customer) {
+			t.Errorf("Invalid trade event: %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Trade event not emitted after confirmation")
+	}
+	buyback := game.Buyback(merchant)
+	if len(buyback) != 1 || buyback[0].Item.Serial() != it.Serial() {
+		t.Errorf("Invalid buyback list after trade confirmation: %v", buyback)
+	}
+}
+
+// newLoopbackGame creates new game with one area connected
+// to the server via loopback transport.
+func newLoopbackGame(t *testing.T) (*Game, *Loopback) {
+	mod := flame.NewModule(res.ModuleData{})
+	mod.Chapter().AddAreas(area.New(res.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	game := New(mod)
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	game.SetServer(server)
+	return game, loopback
+}
+
+// waitFor waits until specified condition is met, test
+// fails if the condition is not met after one second.
+func waitFor(t *testing.T, name string, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", name)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * runner.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/character"
+	flamedata "github.com/isangeles/flame/data"
+	flameres "github.com/isangeles/flame/data/res"
+)
+
+// Struct for headless game runner.
+// Runner updates the game step by step, without the game
+// loop, window or GUI data.
+type Runner struct {
+	game   *Game
+	tick   int64
+	delta  int64
+	inputs map[int64][]func(g *Game)
+	onTick func(g *Game, tick int64)
+}
+
+// NewRunner creates new headless runner for specified game.
+func NewRunner(game *Game) *Runner {
+	r := Runner{
+		game:   game,
+		delta:  stepTime.Milliseconds(),
+		inputs: make(map[int64][]func(g *Game)),
+	}
+	return &r
+}
+
+// NewModuleRunner creates new headless runner for the game
+// with module from directory under specified path.
+func NewModuleRunner(path string) (*Runner, error) {
+	modData, err := flamedata.ImportModuleDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to import module: %v", err)
+	}
+	mod := flame.NewModule(modData)
+	return NewRunner(New(mod)), nil
+}
+
+// Game returns runner game.
+func (r *Runner) Game() *Game {
+	return r.game
+}
+
+// Tick returns number of ticks executed by the runner.
+func (r *Runner) Tick() int64 {
+	return r.tick
+}
+
+// SetDelta sets time in milliseconds that passes in the game
+// with each tick.
+func (r *Runner) SetDelta(delta int64) {
+	r.delta = delta
+}
+
+// SetOnTickFunc sets function triggered after each tick.
+func (r *Runner) SetOnTickFunc(f func(g *Game, tick int64)) {
+	r.onTick = f
+}
+
+// SpawnPlayer creates new player character from specified data
+// and spawns it in the start area of the current chapter.
+func (r *Runner) SpawnPlayer(data flameres.CharacterData) (*Character, error) {
+	char := NewCharacter(character.New(data), r.game)
+	err := r.game.SpawnChar(char)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to spawn character: %v", err)
+	}
+	err = r.game.AddPlayerChar(char)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to add player character: %v", err)
+	}
+	return char, nil
+}
+
+// At schedules specified input function to be executed before
+// the tick with specified number.
+func (r *Runner) At(tick int64, f func(g *Game)) {
+	r.inputs[tick] = append(r.inputs[tick], f)
+}
+
+// RunTicks executes specified number of ticks.
+func (r *Runner) RunTicks(n int64) {
+	for i := int64(0); i < n; i++ {
+		r.step()
+	}
+}
+
+// RunUntil executes ticks until specified condition is met
+// or max number of ticks is reached.
+// Returns true if the condition was met.
+func (r *Runner) RunUntil(cond func(g *Game) bool, maxTicks int64) bool {
+	for i := int64(0); i < maxTicks; i++ {
+		if cond(r.game) {
+			return true
+		}
+		r.step()
+	}
+	return cond(r.game)
+}
+
+// step executes scheduled inputs for the current tick and
+// updates the game.
+func (r *Runner) step() {
+	for _, f := range r.inputs[r.tick] {
+		f(r.game)
+	}
+	delete(r.inputs, r.tick)
+	r.game.Step(r.delta)
+	r.tick++
+	if r.onTick != nil {
+		r.onTick(r.game, r.tick)
+	}
+}
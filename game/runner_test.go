@@ -0,0 +1,73 @@
+/*
+ * runner_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/data/res"
+)
+
+// testRunner creates runner for the test module with one
+// area and one player spawned in that area.
+func testRunner(tb testing.TB) (*Runner, *Character) {
+	mod := flame.NewModule(res.ModuleData{})
+	mod.Chapter().AddAreas(area.New(res.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	runner := NewRunner(New(mod))
+	pc, err := runner.SpawnPlayer(res.CharacterData{ID: "player", Level: 1})
+	if err != nil {
+		tb.Fatalf("Unable to spawn player: %v", err)
+	}
+	return runner, pc
+}
+
+// TestRunnerRunUntil tests running game until player reaches
+// destination point set by the scheduled input.
+func TestRunnerRunUntil(t *testing.T) {
+	runner, pc := testRunner(t)
+	x, y := pc.Position()
+	runner.At(10, func(g *Game) {
+		pc.SetDestPoint(x+10, y)
+	})
+	reached := runner.RunUntil(func(g *Game) bool {
+		posX, _ := pc.Position()
+		return posX >= x+10
+	}, 1000)
+	if !reached {
+		t.Errorf("Player not moved after %d ticks", runner.Tick())
+	}
+	if runner.Tick() <= 10 {
+		t.Errorf("Player moved before scheduled input: tick %d", runner.Tick())
+	}
+}
+
+// BenchmarkRunner benchmarks single game tick.
+func BenchmarkRunner(b *testing.B) {
+	runner, _ := testRunner(b)
+	b.ResetTimer()
+	runner.RunTicks(int64(b.N))
+}
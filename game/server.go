@@ -20,92 +20,572 @@
  *
  *
  */
-
 package game
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/isangeles/flame/data/res/lang"
 
 	"github.com/isangeles/fire/request"
 	"github.com/isangeles/fire/response"
 
+	"github.com/isangeles/mural/config"
 	"github.com/isangeles/mural/log"
 )
 
+// Type for server connection state.
+type ConnState int
+
+const (
+	Connected ConnState = iota
+	Reconnecting
+	Lost
+)
+
+var (
+	// Reconnect backoff.
+	reconnectDelay    = time.Second
+	reconnectMaxDelay = 30 * time.Second
+	reconnectAttempts = 10
+	// Max number of sub-requests in the outbound queue.
+	maxQueueSize = 500
+)
+
+// Info returns translated information about the connection state.
+func (cs ConnState) Info() string {
+	switch cs {
+	case Reconnecting:
+		return lang.Text("server_reconnecting_info")
+	case Lost:
+		return lang.Text("server_lost_info")
+	default:
+		return lang.Text("server_connected_info")
+	}
+}
+
 // Struct for server connection.
 type Server struct {
-	conn       *websocket.Conn
-	authorized bool
-	onResponse func(r response.Response)
+	dial          DialFunc
+	address       string
+	conn          Transport
+	mutex         sync.RWMutex
+	state         ConnState
+	authorized    bool
+	closed        bool
+	login         *request.Login
+	queue         *request.Request
+	replaceQueue  map[string]request.Request
+	replaceKeys   []string
+	queueMutex    sync.Mutex
+	sent          atomic.Int64
+	merged        atomic.Int64
+	received      atomic.Int64
+	statsMutex    sync.Mutex
+	stats         NetStats
+	lastSample    netSample
+	recorder      *Recorder
+	onResponse    func(r response.Response)
+	onStateChange func(state ConnState)
 }
 
 // NewServer creates new server struct with connection to
 // server with specified host and port number.
 func NewServer(host, port string, tls bool) (*Server, error) {
-	s := new(Server)
-	scheme := "ws"
-	if tls {
-		scheme = "wss"
+	return NewRecordedServer(host, port, tls, nil)
+}
+
+// NewRecordedServer creates new server struct with connection to
+// server with specified host and port number.
+// All requests and responses are recorded with specified recorder,
+// starting with the first response from the server.
+func NewRecordedServer(host, port string, tls bool, recorder *Recorder) (*Server, error) {
+	url := serverURL(host, port, tls)
+	dial := func() (Transport, error) {
+		return DialWebsocket(url)
+	}
+	return newServer(dial, recorder)
+}
+
+// ProbeServer checks if the server with specified host and
+// port number is reachable by opening and closing the new
+// connection.
+// Returns time of opening the connection.
+func ProbeServer(host, port string, tls bool, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := dialWebsocket(serverURL(host, port, tls), timeout)
+	if err != nil {
+		return 0, err
 	}
-	url := fmt.Sprintf("%s://%s:%s/", scheme, host, port)
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	defer conn.Close()
+	return time.Since(start), nil
+}
+
+// NewTransportServer creates new server struct with connection
+// opened by the specified dial function.
+// The dial function is also used to reconnect after the connection
+// is lost.
+func NewTransportServer(dial DialFunc) (*Server, error) {
+	return newServer(dial, nil)
+}
+
+// newServer creates new server struct with connection opened by
+// the specified dial function and specified session recorder.
+// Recorder is set before handling of the server responses starts,
+// so no response is missed in the recording.
+func newServer(dial DialFunc, recorder *Recorder) (*Server, error) {
+	s := Server{dial: dial, recorder: recorder}
+	err := s.connect()
 	if err != nil {
-		return nil, fmt.Errorf("Unable to dial server: %v", err)
+		return nil, err
 	}
-	s.conn = conn
+	s.address = s.conn.Address()
 	go s.handleResponses()
-	return s, nil
+	go s.monitor()
+	return &s, nil
 }
 
 // Address returns server address.
 func (s *Server) Address() string {
-	return s.conn.RemoteAddr().String()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.conn == nil {
+		return s.address
+	}
+	return s.conn.Address()
 }
 
 // Authorized checks if server connection is authorized.
 func (s *Server) Authorized() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.authorized
 }
 
+// State returns current state of the server connection.
+func (s *Server) State() ConnState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state
+}
+
 // SetOnResponseFunc sets function triggered on each server response.
 func (s *Server) SetOnResponseFunc(f func(r response.Response)) {
 	s.onResponse = f
 }
 
-// Send sends specified request to the server.
+// SetOnStateChangeFunc sets function triggered on each
+// connection state change.
+func (s *Server) SetOnStateChangeFunc(f func(state ConnState)) {
+	s.onStateChange = f
+}
+
+// SetRecorder sets recorder for all requests sent to the
+// server and all server responses.
+// Nil value disables recording.
+func (s *Server) SetRecorder(r *Recorder) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.recorder = r
+}
+
+// Recorder returns current session recorder.
+func (s *Server) Recorder() *Recorder {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.recorder
+}
+
+// Sent returns number of messages sent to the server.
+func (s *Server) Sent() int64 {
+	return s.sent.Load()
+}
+
+// Merged returns number of requests merged with other
+// requests before sending.
+func (s *Server) Merged() int64 {
+	return s.merged.Load()
+}
+
+// Queue adds specified request to the outbound queue.
+// All queued requests are merged and sent to the server
+// as one request on flush.
+// Requests that can't be merged are sent right away, after
+// all queued requests.
+// Returns error if the queue is full, e.g. after the requests
+// piled up while the connection was lost.
+func (s *Server) Queue(req request.Request) error {
+	if !mergeable(req) {
+		err := s.Flush()
+		if err != nil {
+			return err
+		}
+		return s.write(req)
+	}
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	if s.queueSize()+requestSize(req) > maxQueueSize {
+		return fmt.Errorf("Outbound queue is full")
+	}
+	if s.queue == nil {
+		s.queue = new(request.Request)
+		mergeRequests(s.queue, req)
+		return nil
+	}
+	mergeRequests(s.queue, req)
+	s.merged.Add(1)
+	return nil
+}
+
+// QueueReplace adds specified request to the outbound queue,
+// replacing the request queued earlier with the same key.
+// Used for requests superseded by the newer ones, like moves
+// of the same character, so only the last of them is sent,
+// also after the connection is restored.
+// Requests that can't be merged are sent right away.
+func (s *Server) QueueReplace(key string, req request.Request) error {
+	if !mergeable(req) {
+		return s.Queue(req)
+	}
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	if _, ok := s.replaceQueue[key]; ok {
+		s.replaceQueue[key] = req
+		s.merged.Add(1)
+		return nil
+	}
+	if s.queueSize()+requestSize(req) > maxQueueSize {
+		return fmt.Errorf("Outbound queue is full")
+	}
+	if s.replaceQueue == nil {
+		s.replaceQueue = make(map[string]request.Request)
+	}
+	s.replaceQueue[key] = req
+	s.replaceKeys = append(s.replaceKeys, key)
+	return nil
+}
+
+// Flush sends all queued requests to the server.
+// If sending fails, the requests are put back in front of
+// the queue, to send them with the next flush.
+func (s *Server) Flush() error {
+	s.queueMutex.Lock()
+	queue := s.queue
+	replaceQueue, replaceKeys := s.replaceQueue, s.replaceKeys
+	s.queue = nil
+	s.replaceQueue, s.replaceKeys = nil, nil
+	s.queueMutex.Unlock()
+	req := request.Request{}
+	if queue != nil {
+		mergeRequests(&req, *queue)
+	}
+	for _, k := range replaceKeys {
+		mergeRequests(&req, replaceQueue[k])
+	}
+	if emptyRequest(req) {
+		return nil
+	}
+	err := s.write(req)
+	if err != nil {
+		s.requeue(queue, replaceQueue, replaceKeys)
+		return err
+	}
+	return nil
+}
+
+// Send sends specified request to the server right away,
+// together with all queued requests.
+// Used for requests that shouldn't wait for the next game
+// update, like chat messages.
 func (s *Server) Send(req request.Request) error {
-	text, err := request.Marshal(&req)
+	err := s.Queue(req)
+	if err != nil {
+		return err
+	}
+	return s.Flush()
+}
+
+// requeue puts specified requests in front of the outbound
+// queue.
+// Replaceable requests already replaced by the newer ones
+// queued in the meantime are dropped.
+func (s *Server) requeue(queue *request.Request, replaceQueue map[string]request.Request,
+	replaceKeys []string) {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	if queue != nil {
+		req := *queue
+		if s.queue != nil {
+			mergeRequests(&req, *s.queue)
+		}
+		s.queue = &req
+	}
+	keys := make([]string, 0, len(replaceKeys)+len(s.replaceKeys))
+	for _, k := range replaceKeys {
+		if _, ok := s.replaceQueue[k]; ok {
+			continue
+		}
+		if s.replaceQueue == nil {
+			s.replaceQueue = make(map[string]request.Request)
+		}
+		s.replaceQueue[k] = replaceQueue[k]
+		keys = append(keys, k)
+	}
+	s.replaceKeys = append(keys, s.replaceKeys...)
+}
+
+// queueSize returns number of sub-requests in the outbound
+// queue.
+// Queue mutex must be locked by the caller.
+func (s *Server) queueSize() int {
+	size := 0
+	if s.queue != nil {
+		size += requestSize(*s.queue)
+	}
+	for _, r := range s.replaceQueue {
+		size += requestSize(r)
+	}
+	return size
+}
+
+// write writes specified request to the server connection.
+func (s *Server) write(req request.Request) error {
+	s.mutex.Lock()
+	conn := s.conn
+	state := s.state
+	if len(req.Login) > 0 {
+		login := req.Login[len(req.Login)-1]
+		s.login = &login
+	}
+	s.mutex.Unlock()
+	if conn == nil || state != Connected {
+		return fmt.Errorf("Not connected to the server")
+	}
+	err := conn.Send(req)
+	if err != nil {
+		return err
+	}
+	s.sent.Add(1)
+	if recorder := s.Recorder(); recorder != nil {
+		err := recorder.RecordRequest(req)
+		if err != nil {
+			log.Err.Printf("Server: %s: unable to record request: %v",
+				s.Address(), err)
+		}
+	}
+	return nil
+}
+
+// Close closes the server connection and the session
+// recorder, if set.
+// Closed server will not try to reconnect.
+func (s *Server) Close() error {
+	defer s.stateChanged(Lost)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	s.state = Lost
+	s.authorized = false
+	if s.recorder != nil {
+		err := s.recorder.Close()
+		if err != nil {
+			log.Err.Printf("Server: unable to close session recorder: %v", err)
+		}
+		s.recorder = nil
+	}
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// Logout ends the current user session by replacing the
+// server connection with the new one, without login.
+func (s *Server) Logout() error {
+	conn, err := s.dial()
 	if err != nil {
-		return fmt.Errorf("Unable to marshal request: %v", err)
+		return err
 	}
-	err = s.conn.WriteMessage(websocket.TextMessage, []byte(text))
+	s.mutex.Lock()
+	oldConn := s.conn
+	s.conn = conn
+	s.state = Connected
+	s.authorized = false
+	s.login = nil
+	s.mutex.Unlock()
+	s.stateChanged(Connected)
+	if oldConn != nil {
+		oldConn.Close()
+	}
+	return nil
+}
+
+// connect opens new connection to the server.
+func (s *Server) connect() error {
+	conn, err := s.dial()
 	if err != nil {
-		return fmt.Errorf("Unable to write request: %v", err)
+		return err
 	}
+	s.mutex.Lock()
+	s.conn = conn
+	s.state = Connected
+	s.mutex.Unlock()
+	s.stateChanged(Connected)
 	return nil
 }
 
 // handleResponses handles responses from the server connection and
 // calls onResponse function for each response.
+// On the connection error it tries to reconnect to the server.
 func (s *Server) handleResponses() {
 	for {
-		_, msg, err := s.conn.ReadMessage()
-		if err != nil {
-			log.Err.Printf("Server response: %s: Unable to read from server: %v",
-				s.Address(), err)
-			return
+		s.mutex.RLock()
+		conn := s.conn
+		s.mutex.RUnlock()
+		counter, counting := conn.(CountingTransport)
+		received := int64(0)
+		if counting {
+			received = counter.BytesReceived()
+		}
+		resp, err := conn.Receive()
+		var unmarshalErr *UnmarshalError
+		if errors.As(err, &unmarshalErr) {
+			log.Err.Printf("Server response: %v: %v", s.Address(), err)
+			continue
 		}
-		resp, err := response.Unmarshal(string(msg))
 		if err != nil {
-			log.Err.Printf("Server response: %v: Unable to unmarshal server resonse: %v",
+			if s.isClosed() {
+				return
+			}
+			if s.replaced(conn) {
+				continue
+			}
+			log.Err.Printf("Server response: %s: %v",
 				s.Address(), err)
+			if !s.reconnect() {
+				return
+			}
 			continue
 		}
+		s.received.Add(1)
+		if counting && hasUpdate(resp.Update) {
+			s.recordUpdateSize(counter.BytesReceived() - received)
+		}
+		s.mutex.Lock()
 		s.authorized = !resp.Logon
+		recorder := s.recorder
+		s.mutex.Unlock()
+		if recorder != nil {
+			err := recorder.RecordResponse(resp)
+			if err != nil {
+				log.Err.Printf("Server: %s: unable to record response: %v",
+					s.Address(), err)
+			}
+		}
 		if s.onResponse != nil {
 			go s.onResponse(resp)
 		}
 	}
 }
+
+// reconnect tries to restore the lost server connection with
+// increasing delay between attempts.
+// After successful reconnect the session is resumed by sending
+// the last login request again, in response the server sends
+// back characters of the user.
+// Returns false if all attempts failed or the server was closed.
+func (s *Server) reconnect() bool {
+	s.mutex.Lock()
+	s.state = Reconnecting
+	s.authorized = false
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.mutex.Unlock()
+	s.stateChanged(Reconnecting)
+	delay := reconnectDelay
+	for i := 0; i < reconnectAttempts; i++ {
+		time.Sleep(delay)
+		if s.isClosed() {
+			return false
+		}
+		err := s.connect()
+		if err != nil {
+			log.Err.Printf("Server: %s: reconnect attempt %d failed: %v",
+				s.Address(), i+1, err)
+			delay *= 2
+			if delay > reconnectMaxDelay {
+				delay = reconnectMaxDelay
+			}
+			continue
+		}
+		log.Inf.Printf("Server: %s: reconnected", s.Address())
+		s.resumeSession()
+		return true
+	}
+	s.mutex.Lock()
+	s.state = Lost
+	s.mutex.Unlock()
+	s.stateChanged(Lost)
+	log.Err.Printf("Server: %s: connection lost", s.Address())
+	return false
+}
+
+// resumeSession sends the last login request to the server,
+// if there was no login request the user credentials from
+// the config are used.
+func (s *Server) resumeSession() {
+	s.mutex.RLock()
+	login := s.login
+	s.mutex.RUnlock()
+	if login == nil {
+		if len(config.ServerLogin) < 1 || len(config.ServerPassword) < 1 {
+			return
+		}
+		login = &request.Login{config.ServerLogin, config.ServerPassword}
+	}
+	loginReq := *login
+	req := request.Request{Login: []request.Login{loginReq}}
+	err := s.Send(req)
+	if err != nil {
+		log.Err.Printf("Server: %s: unable to send login request: %v",
+			s.Address(), err)
+	}
+}
+
+// serverURL returns URL of the websocket server with
+// specified host and port.
+func serverURL(host, port string, tls bool) string {
+	scheme := "ws"
+	if tls {
+		scheme = "wss"
+	}
+	return fmt.Sprintf("%s://%s:%s/", scheme, host, port)
+}
+
+// stateChanged calls onStateChange function with
+// specified connection state.
+func (s *Server) stateChanged(state ConnState) {
+	if s.onStateChange != nil {
+		s.onStateChange(state)
+	}
+}
+
+// replaced checks if specified connection was replaced
+// with the new server connection.
+func (s *Server) replaced(conn Transport) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.conn != conn
+}
+
+// isClosed checks if the server was closed.
+func (s *Server) isClosed() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.closed
+}
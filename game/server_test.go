@@ -0,0 +1,433 @@
+/*
+ * server_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestServerSend tests sending requests to the server.
+func TestServerSend(t *testing.T) {
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	req := request.Request{Command: []string{"test"}}
+	err = server.Send(req)
+	if err != nil {
+		t.Fatalf("Unable to send request: %v", err)
+	}
+	reqs := loopback.Requests()
+	if len(reqs) != 1 || len(reqs[0].Command) != 1 || reqs[0].Command[0] != "test" {
+		t.Errorf("Invalid requests received by transport: %v", reqs)
+	}
+}
+
+// TestServerResponse tests handling responses from the server.
+func TestServerResponse(t *testing.T) {
+	loopback := NewLoopback()
+	loopback.SetOnRequestFunc(func(req request.Request) []response.Response {
+		if len(req.Login) < 1 {
+			return nil
+		}
+		return []response.Response{{Logon: false}}
+	})
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	resps := make(chan response.Response, 1)
+	server.SetOnResponseFunc(func(r response.Response) {
+		resps <- r
+	})
+	// Test.
+	loginReq := request.Login{"user", "pass"}
+	err = server.Send(request.Request{Login: []request.Login{loginReq}})
+	if err != nil {
+		t.Fatalf("Unable to send login request: %v", err)
+	}
+	select {
+	case <-resps:
+	case <-time.After(time.Second):
+		t.Fatalf("No response received")
+	}
+	if !server.Authorized() {
+		t.Errorf("Server not authorized after login response")
+	}
+}
+
+// TestServerReconnect tests reconnecting to the server after
+// the connection is lost.
+func TestServerReconnect(t *testing.T) {
+	reconnectDelay = time.Millisecond
+	defer func() { reconnectDelay = time.Second }()
+	loopbacks := make(chan *Loopback, 2)
+	dial := func() (Transport, error) {
+		l := NewLoopback()
+		loopbacks <- l
+		return l, nil
+	}
+	server, err := NewTransportServer(dial)
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	loginReq := request.Login{"user", "pass"}
+	err = server.Send(request.Request{Login: []request.Login{loginReq}})
+	if err != nil {
+		t.Fatalf("Unable to send login request: %v", err)
+	}
+	// Test.
+	first := <-loopbacks
+	first.Close()
+	var second *Loopback
+	select {
+	case second = <-loopbacks:
+	case <-time.After(time.Second):
+		t.Fatalf("Server not reconnected")
+	}
+	for i := 0; i < 100 && len(second.Requests()) < 1; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	reqs := second.Requests()
+	if len(reqs) != 1 || len(reqs[0].Login) != 1 || reqs[0].Login[0] != loginReq {
+		t.Errorf("Login request not resent after reconnect: %v", reqs)
+	}
+	if server.State() != Connected {
+		t.Errorf("Invalid connection state after reconnect: %d", server.State())
+	}
+}
+
+// TestServerQueue tests merging queued requests.
+func TestServerQueue(t *testing.T) {
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	server.Queue(request.Request{Command: []string{"test1"}})
+	server.Queue(request.Request{Command: []string{"test2"}})
+	if len(loopback.Requests()) > 0 {
+		t.Errorf("Queued requests sent before flush")
+	}
+	err = server.Flush()
+	if err != nil {
+		t.Fatalf("Unable to flush requests: %v", err)
+	}
+	reqs := loopback.Requests()
+	if len(reqs) != 1 || len(reqs[0].Command) != 2 {
+		t.Errorf("Queued requests not merged: %v", reqs)
+	}
+	if server.Sent() != 1 {
+		t.Errorf("Invalid number of sent requests: %d != 1", server.Sent())
+	}
+	if server.Merged() != 1 {
+		t.Errorf("Invalid number of merged requests: %d != 1", server.Merged())
+	}
+}
+
+// TestServerFlushError tests keeping queued requests after
+// failed flush.
+func TestServerFlushError(t *testing.T) {
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	server.Queue(request.Request{Command: []string{"test1"}})
+	server.mutex.Lock()
+	server.state = Reconnecting
+	server.mutex.Unlock()
+	err = server.Flush()
+	if err == nil {
+		t.Errorf("No error while flushing without connection")
+	}
+	server.Queue(request.Request{Command: []string{"test2"}})
+	server.mutex.Lock()
+	server.state = Connected
+	server.mutex.Unlock()
+	err = server.Flush()
+	if err != nil {
+		t.Fatalf("Unable to flush requests: %v", err)
+	}
+	reqs := loopback.Requests()
+	if len(reqs) != 1 || len(reqs[0].Command) != 2 ||
+		reqs[0].Command[0] != "test1" || reqs[0].Command[1] != "test2" {
+		t.Errorf("Queued requests lost after failed flush: %v", reqs)
+	}
+}
+
+// TestServerQueueLimit tests limiting size of the outbound
+// queue.
+func TestServerQueueLimit(t *testing.T) {
+	defer func(size int) { maxQueueSize = size }(maxQueueSize)
+	maxQueueSize = 2
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	err = server.Queue(request.Request{Command: []string{"test1", "test2"}})
+	if err != nil {
+		t.Fatalf("Unable to queue request: %v", err)
+	}
+	err = server.Queue(request.Request{Command: []string{"test3"}})
+	if err == nil {
+		t.Errorf("No error for request over queue limit")
+	}
+	err = server.QueueReplace("test", request.Request{Command: []string{"test4"}})
+	if err == nil {
+		t.Errorf("No error for replaceable request over queue limit")
+	}
+	err = server.Flush()
+	if err != nil {
+		t.Fatalf("Unable to flush requests: %v", err)
+	}
+	err = server.Queue(request.Request{Command: []string{"test3"}})
+	if err != nil {
+		t.Errorf("Unable to queue request after flush: %v", err)
+	}
+}
+
+// TestServerQueueReplace tests sending only the last of the
+// replaceable requests after failed flush.
+func TestServerQueueReplace(t *testing.T) {
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	moves := []request.Move{
+		{"char", "0", 10, 10},
+		{"char", "0", 20, 20},
+		{"char", "0", 30, 30},
+	}
+	other := request.Move{"other", "0", 10, 10}
+	// Test.
+	server.mutex.Lock()
+	server.state = Reconnecting
+	server.mutex.Unlock()
+	server.QueueReplace("char", request.Request{Move: moves[:1]})
+	server.QueueReplace("other", request.Request{Move: []request.Move{other}})
+	server.QueueReplace("char", request.Request{Move: moves[1:2]})
+	err = server.Flush()
+	if err == nil {
+		t.Errorf("No error while flushing without connection")
+	}
+	server.QueueReplace("char", request.Request{Move: moves[2:]})
+	server.mutex.Lock()
+	server.state = Connected
+	server.mutex.Unlock()
+	err = server.Flush()
+	if err != nil {
+		t.Fatalf("Unable to flush requests: %v", err)
+	}
+	reqs := loopback.Requests()
+	if len(reqs) != 1 || len(reqs[0].Move) != 2 || reqs[0].Move[0] != moves[2] ||
+		reqs[0].Move[1] != other {
+		t.Errorf("Superseded requests not dropped: %v", reqs)
+	}
+}
+
+// TestServerStats tests connection statistics.
+func TestServerStats(t *testing.T) {
+	loopback := NewLoopback()
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	server.updateStats(loopback)
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		err = server.Send(request.Request{Command: []string{"test"}})
+		if err != nil {
+			t.Fatalf("Unable to send request: %v", err)
+		}
+	}
+	server.updateStats(loopback)
+	stats := server.Stats()
+	if stats.SentMsgs <= 0 {
+		t.Errorf("Invalid number of messages sent per second: %f",
+			stats.SentMsgs)
+	}
+	if stats.ReceivedMsgs != 0 {
+		t.Errorf("Invalid number of messages received per second: %f",
+			stats.ReceivedMsgs)
+	}
+}
+
+// TestServerUpdateSize tests recording size of messages
+// with update.
+func TestServerUpdateSize(t *testing.T) {
+	loopback := &countingLoopback{Loopback: NewLoopback()}
+	server, err := NewTransportServer(func() (Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	resps := make(chan response.Response, 2)
+	server.SetOnResponseFunc(func(r response.Response) {
+		resps <- r
+	})
+	// Test.
+	update := response.Update{Module: res.ModuleData{
+		Config: map[string][]string{"id": {"test"}},
+	}}
+	err = loopback.Respond(response.Response{Update: update})
+	if err != nil {
+		t.Fatalf("Unable to queue update response: %v", err)
+	}
+	<-resps
+	err = loopback.Respond(response.Response{Error: []string{"test"}})
+	if err != nil {
+		t.Fatalf("Unable to queue error response: %v", err)
+	}
+	<-resps
+	stats := server.Stats()
+	if stats.UpdateSize != countingLoopbackSize {
+		t.Errorf("Invalid update size: %d != %d", stats.UpdateSize,
+			countingLoopbackSize)
+	}
+}
+
+// TestServerLogout tests ending the user session.
+func TestServerLogout(t *testing.T) {
+	loopbacks := make(chan *Loopback, 2)
+	dial := func() (Transport, error) {
+		l := NewLoopback()
+		l.SetOnRequestFunc(func(req request.Request) []response.Response {
+			return []response.Response{{Logon: len(req.Login) < 1}}
+		})
+		loopbacks <- l
+		return l, nil
+	}
+	server, err := NewTransportServer(dial)
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	defer server.Close()
+	resps := make(chan response.Response, 2)
+	server.SetOnResponseFunc(func(r response.Response) {
+		resps <- r
+	})
+	loginReq := request.Login{"user", "pass"}
+	err = server.Send(request.Request{Login: []request.Login{loginReq}})
+	if err != nil {
+		t.Fatalf("Unable to send login request: %v", err)
+	}
+	select {
+	case <-resps:
+	case <-time.After(time.Second):
+		t.Fatalf("No login response received")
+	}
+	// Test.
+	err = server.Logout()
+	if err != nil {
+		t.Fatalf("Unable to logout: %v", err)
+	}
+	if server.Authorized() {
+		t.Errorf("Server authorized after logout")
+	}
+	<-loopbacks
+	second := <-loopbacks
+	err = server.Send(request.Request{Command: []string{"test"}})
+	if err != nil {
+		t.Fatalf("Unable to send request after logout: %v", err)
+	}
+	select {
+	case <-resps:
+	case <-time.After(time.Second):
+		t.Fatalf("No response received after logout")
+	}
+	if len(second.Requests()) != 1 {
+		t.Errorf("Invalid requests sent after logout: %v", second.Requests())
+	}
+	if server.Authorized() {
+		t.Errorf("Server authorized after logout")
+	}
+}
+
+// Size of each response received by counting loopback.
+const countingLoopbackSize = 100
+
+// Struct for loopback transport counting fixed number
+// of bytes for each received response.
+type countingLoopback struct {
+	*Loopback
+	received atomic.Int64
+}
+
+// Receive waits for the next queued response.
+func (cl *countingLoopback) Receive() (response.Response, error) {
+	resp, err := cl.Loopback.Receive()
+	if err == nil {
+		cl.received.Add(int64(len(resp.Error)+1) * countingLoopbackSize)
+	}
+	return resp, err
+}
+
+// BytesSent returns number of bytes sent.
+func (cl *countingLoopback) BytesSent() int64 {
+	return 0
+}
+
+// BytesReceived returns number of bytes received.
+func (cl *countingLoopback) BytesReceived() int64 {
+	return cl.received.Load()
+}
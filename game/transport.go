@@ -0,0 +1,199 @@
+/*
+ * transport.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// Interface for server connection transport.
+// Send must be safe for concurrent use, Receive is called
+// only by the server responses goroutine.
+type Transport interface {
+	Send(req request.Request) error
+	Receive() (response.Response, error)
+	Close() error
+	Address() string
+}
+
+// Interface for transports counting bytes sent and
+// received through the connection.
+type CountingTransport interface {
+	BytesSent() int64
+	BytesReceived() int64
+}
+
+// Interface for transports measuring round-trip time
+// of the connection.
+type PingTransport interface {
+	Ping() error
+	RTT() time.Duration
+}
+
+// Type for functions opening new transport connection.
+type DialFunc func() (Transport, error)
+
+const (
+	// Max time for writing ping frame.
+	pingWriteTimeout = time.Second
+)
+
+// Struct for websocket transport.
+type WebsocketTransport struct {
+	conn          *websocket.Conn
+	writeMutex    sync.Mutex
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+	rtt           atomic.Int64
+}
+
+// DialWebsocket creates new websocket transport connected to
+// the specified URL.
+func DialWebsocket(url string) (*WebsocketTransport, error) {
+	return dialWebsocket(url, websocket.DefaultDialer.HandshakeTimeout)
+}
+
+// dialWebsocket creates new websocket transport connected to
+// the specified URL, with specified handshake timeout.
+func dialWebsocket(url string, timeout time.Duration) (*WebsocketTransport, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = timeout
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to dial server: %v", err)
+	}
+	wt := WebsocketTransport{conn: conn}
+	conn.SetPongHandler(wt.handlePong)
+	return &wt, nil
+}
+
+// Send writes specified request to the websocket connection.
+func (wt *WebsocketTransport) Send(req request.Request) error {
+	text, err := request.Marshal(&req)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal request: %v", err)
+	}
+	wt.writeMutex.Lock()
+	defer wt.writeMutex.Unlock()
+	err = wt.conn.WriteMessage(websocket.TextMessage, []byte(text))
+	if err != nil {
+		return fmt.Errorf("Unable to write request: %v", err)
+	}
+	wt.bytesSent.Add(int64(len(text)))
+	return nil
+}
+
+// Receive reads next response from the websocket connection.
+func (wt *WebsocketTransport) Receive() (response.Response, error) {
+	_, msg, err := wt.conn.ReadMessage()
+	if err != nil {
+		return response.Response{}, fmt.Errorf("Unable to read from server: %v", err)
+	}
+	wt.bytesReceived.Add(int64(len(msg)))
+	resp, err := response.Unmarshal(string(msg))
+	if err != nil {
+		return resp, &UnmarshalError{err}
+	}
+	return resp, nil
+}
+
+// Close closes the websocket connection.
+func (wt *WebsocketTransport) Close() error {
+	return wt.conn.Close()
+}
+
+// Address returns address of the remote server.
+func (wt *WebsocketTransport) Address() string {
+	return wt.conn.RemoteAddr().String()
+}
+
+// BytesSent returns number of bytes sent through the
+// websocket connection.
+func (wt *WebsocketTransport) BytesSent() int64 {
+	return wt.bytesSent.Load()
+}
+
+// BytesReceived returns number of bytes received through
+// the websocket connection.
+func (wt *WebsocketTransport) BytesReceived() int64 {
+	return wt.bytesReceived.Load()
+}
+
+// Ping writes ping frame with the current time to the
+// websocket connection.
+// Round-trip time is updated after receiving pong frame
+// from the server.
+func (wt *WebsocketTransport) Ping() error {
+	data := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	wt.writeMutex.Lock()
+	defer wt.writeMutex.Unlock()
+	err := wt.conn.WriteControl(websocket.PingMessage, data,
+		time.Now().Add(pingWriteTimeout))
+	if err != nil {
+		return fmt.Errorf("Unable to write ping: %v", err)
+	}
+	return nil
+}
+
+// RTT returns round-trip time measured with the last
+// pong frame received from the server.
+func (wt *WebsocketTransport) RTT() time.Duration {
+	return time.Duration(wt.rtt.Load())
+}
+
+// handlePong handles pong frame with time of the ping
+// frame.
+func (wt *WebsocketTransport) handlePong(data string) error {
+	sent, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return nil
+	}
+	wt.rtt.Store(time.Now().UnixNano() - sent)
+	return nil
+}
+
+// Struct for response unmarshal error.
+// Unlike other receive errors this error doesn't break
+// the connection.
+type UnmarshalError struct {
+	err error
+}
+
+// Error returns error message.
+func (ue *UnmarshalError) Error() string {
+	return fmt.Sprintf("Unable to unmarshal server resonse: %v", ue.err)
+}
+
+// Unwrap returns the underlying error.
+func (ue *UnmarshalError) Unwrap() error {
+	return ue.err
+}
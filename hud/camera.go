@@ -1,7 +1,7 @@
 /*
  * camera.go
  *
- * Copyright 2018-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2018-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -126,14 +126,16 @@ func (c *Camera) Update(win *mtk.Window) {
 	if c.area != nil {
 		c.area.Update(win)
 	}
-	// Mouse events.
-	if config.Debug && win.JustPressed(pixelgl.MouseButtonLeft) && win.Pressed(debugMoveKey) {
-		c.onDebugMouseLeftPressed(win.MousePosition())
-	} else if !c.locked && win.JustPressed(pixelgl.MouseButtonLeft) {
-		c.onMouseLeftPressed(win.MousePosition())
-	}
-	if !c.locked && win.JustPressed(pixelgl.MouseButtonRight) {
-		c.onMouseRightPressed(win.MousePosition())
+	// Mouse events(spectator can't take any actions).
+	if !c.hud.Game().Spectator() {
+		if config.Debug && win.JustPressed(pixelgl.MouseButtonLeft) && win.Pressed(debugMoveKey) {
+			c.onDebugMouseLeftPressed(win.MousePosition())
+		} else if !c.locked && win.JustPressed(pixelgl.MouseButtonLeft) {
+			c.onMouseLeftPressed(win.MousePosition())
+		}
+		if !c.locked && win.JustPressed(pixelgl.MouseButtonRight) {
+			c.onMouseRightPressed(win.MousePosition())
+		}
 	}
 	// Debug.
 	c.cameraInfo.SetText(fmt.Sprintf("Camera: %.2f, %.2f",
@@ -162,6 +164,7 @@ func (c *Camera) SetArea(a *area.Area) error {
 		return fmt.Errorf("unable to create pc area map: %v", err)
 	}
 	c.area = object.NewArea(c.hud.game, a, areaMap)
+	c.hud.game.SetPathFinder(c.area)
 	// Center camera at player
 	pcAvatar := c.hud.PCAvatar()
 	if pcAvatar != nil {
@@ -296,7 +299,7 @@ func (c *Camera) onMouseLeftPressed(pos pixel.Vec) {
 	// Move active PC.
 	destPos := c.ConvCameraPos(pos)
 	if !c.hud.game.Pause() && c.area.PassablePosition(destPos) {
-		c.hud.Game().ActivePlayerChar().SetDestPoint(destPos.X, destPos.Y)
+		c.hud.Game().MoveGroup(c.hud.party.Selected(), destPos.X, destPos.Y)
 	}
 }
 
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gopxl/pixel"
@@ -47,15 +48,33 @@ import (
 
 	"github.com/isangeles/mural/config"
 	"github.com/isangeles/mural/data"
+	"github.com/isangeles/mural/data/res"
 	"github.com/isangeles/mural/data/res/graphic"
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/hud/internal"
+	"github.com/isangeles/mural/input"
 	"github.com/isangeles/mural/log"
+	"github.com/isangeles/mural/object"
 )
 
 var (
 	chatKey           = pixelgl.KeyEnter
+	chatSearchKey     = pixelgl.KeyF
+	chatSearchModKey  = pixelgl.KeyLeftControl
 	chatCommandPrefix = "$"
 	guiCommandPrefix  = "gui"
 	chatScriptPrefix  = "%"
+	chatTabPrefix     = "/tab"
+	chatMaxTabs       = 5
+	chatBufferSize    = 500
+	chatSyncTime      = int64(500)
+)
+
+// IDs of avatar logs collected by chat.
+const (
+	chatLogID = iota
+	combatLogID
+	privateLogID
 )
 
 // Chat represents HUD chat window.
@@ -67,8 +86,36 @@ type Chat struct {
 	textbox   *mtk.Textbox
 	textedit  *mtk.Textedit
 	activated bool
-	lastInput string
-	messages  []Message
+	history   *input.History
+	completer *input.Completer
+	tabs      []*chatTab
+	activeTab *chatTab
+	// Scrollback search.
+	search     *mtk.Textedit
+	searching  bool
+	searchText string
+	collector  *internal.Collector
+	buffer     *internal.Ring[Message]
+	// Messages sources, accessed by the collecting goroutine.
+	sourcesMutex sync.RWMutex
+	sources      map[string]*object.Avatar
+	syncTimer    int64
+	sysTime      time.Time
+	// Sequence number of the next message to add to the
+	// textbox and number of messages in the textbox.
+	shownSeq uint64
+	shown    int
+	// Session transcript, written by the collecting goroutine.
+	transcriptMutex sync.Mutex
+	transcript      *transcript
+}
+
+// Struct for chat tab with messages
+// from selected channels.
+type chatTab struct {
+	name     string
+	channels map[game.ChatChannel]bool
+	button   *mtk.Button
 }
 
 // Interface for objects with combat log.
@@ -79,18 +126,12 @@ type CombatLogger interface {
 
 // Struct for log message.
 type Message struct {
-	author string
-	time   time.Time
-	text   string
+	author  string
+	channel game.ChatChannel
+	time    time.Time
+	text    string
 }
 
-// Struct for sorting messages by the messsage time.
-type MessagesByTime []Message
-
-func (mbt MessagesByTime) Len() int           { return len(mbt) }
-func (mbt MessagesByTime) Swap(i, j int)      { mbt[i], mbt[j] = mbt[j], mbt[i] }
-func (mbt MessagesByTime) Less(i, j int) bool { return mbt[i].time.UnixNano() < mbt[j].time.UnixNano() }
-
 // newChat creates new chat window for HUD.
 func newChat(hud *HUD) *Chat {
 	c := new(Chat)
@@ -123,19 +164,67 @@ func newChat(hud *HUD) *Chat {
 	}
 	// Textedit.
 	c.textedit = mtk.NewTextedit(textboxParams)
-	// Listen to avatars chat channels.
-	go c.listenCharsChat()
+	// Search.
+	searchParams := mtk.Params{
+		FontSize:    mtk.SizeSmall,
+		MainColor:   mainColor,
+		AccentColor: accentColor,
+	}
+	c.search = mtk.NewTextedit(searchParams)
+	// Input history and completion.
+	c.history = input.NewHistory(config.HistorySize)
+	c.completer = input.NewCompleter()
+	c.completer.SetTargetsFunc(c.completionTargets)
+	// Tabs.
+	c.setTabs(defaultChatTabs())
+	// Messages.
+	c.buffer = internal.NewRing[Message](chatBufferSize)
+	c.sources = make(map[string]*object.Avatar)
+	c.collector = internal.NewCollector()
+	go c.collectMessages()
+	// Session transcript.
+	if len(config.ChatTranscript) > 0 {
+		channels := make([]game.ChatChannel, 0)
+		for _, ch := range config.ChatTranscript {
+			channels = append(channels, game.ChatChannel(ch))
+		}
+		t, err := newTranscript(config.TranscriptFormat, channels...)
+		if err != nil {
+			log.Err.Printf("hud: chat: unable to create session transcript: %v", err)
+		}
+		c.transcript = t
+	}
 	return c
 }
 
 // Draw draws chat window.
 func (c *Chat) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	c.drawArea = mtk.MatrixToDrawArea(matrix, c.Size())
 	// Background.
 	if c.bgSpr != nil {
 		c.bgSpr.Draw(win, matrix)
 	}
 	// Textbox.
 	c.textbox.Draw(win, matrix)
+	// Tabs.
+	for i, t := range c.tabs {
+		if i == 0 {
+			tabMove := pixel.V(-c.Size().X/2+t.button.Size().X/2+mtk.ConvSize(10),
+				c.Size().Y/2-mtk.ConvSize(15))
+			t.button.Draw(win, matrix.Moved(tabMove))
+			continue
+		}
+		tabPos := mtk.RightOf(c.tabs[i-1].button.DrawArea(), t.button.Size(), 5)
+		t.button.Draw(win, mtk.Matrix().Moved(tabPos))
+	}
+	// Search.
+	if c.searching || len(c.searchText) > 0 {
+		searchSize := pixel.V(c.Size().X/3, mtk.ConvSize(20))
+		c.search.SetSize(searchSize)
+		searchMove := pixel.V(c.Size().X/2-searchSize.X/2-mtk.ConvSize(10),
+			c.Size().Y/2-mtk.ConvSize(15))
+		c.search.Draw(win, matrix.Moved(searchMove))
+	}
 	// Textedit.
 	editSize := pixel.V(c.Size().X, mtk.ConvSize(30))
 	c.textedit.SetSize(editSize)
@@ -146,39 +235,74 @@ func (c *Chat) Draw(win *mtk.Window, matrix pixel.Matrix) {
 // Update updates chat window.
 func (c *Chat) Update(win *mtk.Window) {
 	// Key events.
+	if c.searching {
+		c.updateSearchKeys(win)
+	} else {
+		c.updateInputKeys(win)
+	}
+	// Messages.
+	c.syncTimer += win.Delta()
+	if c.syncTimer >= chatSyncTime {
+		c.syncSources()
+		c.addSystemMessages()
+		c.syncTimer = 0
+	}
+	if c.search.Text() != c.searchText {
+		c.searchText = c.search.Text()
+		c.resetTextbox()
+	}
+	c.updateTextbox()
+	// Elements update.
+	c.textbox.Update(win)
+	for _, t := range c.tabs {
+		t.button.Update(win)
+	}
+	if c.activated {
+		c.textedit.Update(win)
+	}
+	if c.searching {
+		c.search.Update(win)
+	}
+	// Open search after the elements update, so the
+	// search key is not typed into the search box.
+	if !c.searching && win.Pressed(chatSearchModKey) && win.JustPressed(chatSearchKey) {
+		c.Activate(false)
+		c.Search(true)
+	}
+}
+
+// updateInputKeys handles key events for the chat input.
+func (c *Chat) updateInputKeys(win *mtk.Window) {
 	if win.JustPressed(chatKey) {
 		c.onChatKeyPressed()
 	}
 	if win.JustPressed(pixelgl.KeyEscape) {
 		c.Activate(false)
 	}
-	// Clear textbox.
-	scrollBottom := c.textbox.AtBottom()
-	c.textbox.Clear()
-	messages := make([]Message, 0)
-	messages = append(messages, c.messages...)
-	// Add engine log messages.
-	for _, m := range flamelog.Messages() {
-		m := Message{
-			author: "system",
-			time:   m.Date(),
-			text:   m.String(),
-		}
-		messages = append(messages, m)
+	if !c.activated {
+		return
 	}
-	// Sort and print messages.
-	sort.Sort(MessagesByTime(messages))
-	for _, m := range messages {
-		c.textbox.AddText(fmt.Sprintf("%s: %s", lang.Text(m.author),
-			m.text))
+	if win.JustPressed(pixelgl.KeyUp) {
+		c.textedit.SetText(c.history.Prev())
 	}
-	if scrollBottom {
-		c.textbox.ScrollBottom()
+	if win.JustPressed(pixelgl.KeyDown) {
+		c.textedit.SetText(c.history.Next())
 	}
-	// Elements update.
-	c.textbox.Update(win)
-	if c.Activated() {
-		c.textedit.Update(win)
+	if win.JustPressed(pixelgl.KeyTab) {
+		c.complete()
+	}
+}
+
+// updateSearchKeys handles key events for the search box.
+// Enter key closes the search box and keeps the search
+// filter, escape key also clears the filter.
+func (c *Chat) updateSearchKeys(win *mtk.Window) {
+	if win.JustPressed(chatKey) {
+		c.Search(false)
+	}
+	if win.JustPressed(pixelgl.KeyEscape) {
+		c.search.Clear()
+		c.Search(false)
 	}
 }
 
@@ -196,18 +320,34 @@ func (c *Chat) Size() pixel.Vec {
 	return mtk.ConvVec(c.bgSpr.Frame().Size())
 }
 
-// Activated checks whether chat input is
-// active.
+// Activated checks whether chat input or search
+// box is active.
 func (c *Chat) Activated() bool {
-	return c.activated
+	return c.activated || c.searching
 }
 
 // Active toggles chat intput activity.
 func (c *Chat) Activate(active bool) {
 	c.activated = active
-	c.textedit.Focus(c.Activated())
-	c.hud.Camera().Lock(c.Activated())
-	c.hud.bar.Lock(c.Activated())
+	c.textedit.Focus(active)
+	c.hud.Camera().Lock(active)
+	c.hud.bar.Lock(active)
+}
+
+// Search toggles chat scrollback search box activity.
+// Only messages containing the search box text are
+// displayed.
+func (c *Chat) Search(search bool) {
+	c.searching = search
+	c.search.Focus(search)
+	c.hud.Camera().Lock(search)
+	c.hud.bar.Lock(search)
+}
+
+// SetHistory sets specified history as chat
+// input history.
+func (c *Chat) SetHistory(h *input.History) {
+	c.history = h
 }
 
 // Echo displays specified text in chat log.
@@ -215,42 +355,331 @@ func (c *Chat) Echo(text string) {
 	log.Inf.Printf("%s", text)
 }
 
-// listenCharChat listens to avatars chat channels.
-func (c *Chat) listenCharsChat() {
-	for {
-		if c.hud.camera.area == nil {
+// syncSources sets avatars from the current area as
+// sources of the collected messages.
+func (c *Chat) syncSources() {
+	if c.hud.camera.area == nil {
+		return
+	}
+	sources := make(map[string]*object.Avatar)
+	logs := make(map[string][]*objects.Log)
+	for _, a := range c.hud.camera.area.Avatars() {
+		key := a.ID() + a.Serial()
+		sources[key] = a
+		// Order of logs must match log IDs.
+		logs[key] = []*objects.Log{a.ChatLog(), a.CombatLog(), a.PrivateLog()}
+	}
+	c.sourcesMutex.Lock()
+	c.sources = sources
+	c.sourcesMutex.Unlock()
+	c.collector.Sync(logs)
+}
+
+// collectMessages adds messages collected from visible
+// avatars to the chat buffer.
+// Blocks until the collector is closed.
+func (c *Chat) collectMessages() {
+	for m := range c.collector.Messages() {
+		c.sourcesMutex.RLock()
+		a := c.sources[m.Source]
+		c.sourcesMutex.RUnlock()
+		if a == nil || !c.hud.game.VisibleForPlayer(a.Position().X, a.Position().Y) {
 			continue
 		}
-		for _, a := range c.hud.camera.area.Avatars() {
-			if !c.hud.game.VisibleForPlayer(a.Position().X, a.Position().Y) {
-				continue
-			}
-			select {
-			case msg := <-a.ChatLog().Channel():
-				c.addObjectMessage(a.ID(), msg)
-			case msg := <-a.CombatLog().Channel():
-				c.addObjectMessage(a.ID(), msg)
-			case msg := <-a.PrivateLog().Channel():
-				if c.hud.playerObject(a.ID(), a.Serial()) {
-					c.addObjectMessage(a.ID(), msg)
+		msg := m.Message
+		switch m.Log {
+		case chatLogID:
+			chatMsg := game.ParseChat(msg.String())
+			if c.hud.game.ChatVisible(a.ID(), a.Serial(), chatMsg) {
+				msg.Text = chatMsg.Text
+				if chatMsg.Channel == game.ChatWhisper {
+					msg.Text = fmt.Sprintf("@%s %s", chatMsg.Recipient, chatMsg.Text)
 				}
-			default:
+				c.addObjectMessage(a.ID(), chatMsg.Channel, msg)
+			}
+		case combatLogID:
+			c.addObjectMessage(a.ID(), game.ChatCombat, msg)
+		case privateLogID:
+			if c.hud.playerObject(a.ID(), a.Serial()) {
+				c.addObjectMessage(a.ID(), game.ChatSystem, msg)
 			}
 		}
 	}
 }
 
+// addSystemMessages adds new engine log messages
+// to the chat buffer, ordered by time.
+func (c *Chat) addSystemMessages() {
+	messages := make([]Message, 0)
+	for _, m := range flamelog.Messages() {
+		if !m.Date().After(c.sysTime) {
+			continue
+		}
+		msg := Message{
+			author:  "system",
+			channel: game.ChatSystem,
+			time:    m.Date(),
+			text:    m.String(),
+		}
+		messages = append(messages, msg)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].time.Before(messages[j].time)
+	})
+	for _, m := range messages {
+		c.addMessage(m)
+		c.sysTime = m.time
+	}
+}
+
+// updateTextbox adds new messages from the active tab
+// channels to the textbox.
+// Textbox is rebuilt from the chat buffer after exceeding
+// the buffer size twice.
+func (c *Chat) updateTextbox() {
+	messages, next := c.buffer.Since(c.shownSeq)
+	if len(messages) < 1 {
+		return
+	}
+	if c.shown+len(messages) > chatBufferSize*2 {
+		c.resetTextbox()
+		messages, next = c.buffer.Since(0)
+	}
+	scrollBottom := c.textbox.AtBottom()
+	for _, m := range messages {
+		if c.activeTab != nil && !c.activeTab.channels[m.channel] {
+			continue
+		}
+		if !c.matchSearch(m) {
+			continue
+		}
+		c.textbox.AddText(m.String())
+		c.shown++
+	}
+	c.shownSeq = next
+	if scrollBottom {
+		c.textbox.ScrollBottom()
+	}
+}
+
+// resetTextbox clears the textbox, all messages from
+// the chat buffer will be added to the textbox with the
+// next update.
+func (c *Chat) resetTextbox() {
+	c.textbox.Clear()
+	c.shownSeq = 0
+	c.shown = 0
+}
+
+// Add adds new message with specified channel and author to
+// the chat log.
+func (c *Chat) Add(channel game.ChatChannel, author, text string) {
+	msg := Message{
+		author:  author,
+		channel: channel,
+		time:    time.Now(),
+		text:    fmt.Sprintf("%s\n", text),
+	}
+	c.addMessage(msg)
+}
+
+// Export writes all messages from specified channels in the
+// chat buffer to the new transcript file with specified format.
+// Messages from all channels are exported if no channels are
+// specified.
+// Incomplete transcript file is removed on failure.
+// Returns path to the transcript file.
+func (c *Chat) Export(format string, channels ...game.ChatChannel) (string, error) {
+	t, err := newTranscript(format, channels...)
+	if err != nil {
+		return "", fmt.Errorf("Unable to create transcript: %v", err)
+	}
+	messages, _ := c.buffer.Since(0)
+	for _, m := range messages {
+		err := t.Write(m)
+		if err != nil {
+			t.Remove()
+			return "", fmt.Errorf("Unable to write transcript: %v", err)
+		}
+	}
+	err = t.Close()
+	if err != nil {
+		t.Remove()
+		return "", fmt.Errorf("Unable to close transcript: %v", err)
+	}
+	return t.Path(), nil
+}
+
+// Close stops collecting messages and closes the session
+// transcript.
+func (c *Chat) Close() {
+	c.collector.Close()
+	c.transcriptMutex.Lock()
+	defer c.transcriptMutex.Unlock()
+	if c.transcript == nil {
+		return
+	}
+	err := c.transcript.Close()
+	if err != nil {
+		log.Err.Printf("hud: chat: unable to close session transcript: %v", err)
+	}
+	c.transcript = nil
+}
+
+// addMessage adds specified message to the chat buffer and
+// the session transcript.
+// Transcript is closed after the first write error.
+func (c *Chat) addMessage(m Message) {
+	c.buffer.Add(m)
+	c.transcriptMutex.Lock()
+	defer c.transcriptMutex.Unlock()
+	if c.transcript == nil {
+		return
+	}
+	err := c.transcript.Write(m)
+	if err == nil {
+		return
+	}
+	log.Err.Printf("hud: chat: unable to write session transcript: %v", err)
+	c.transcript.Close()
+	c.transcript = nil
+}
+
+// Data returns data with chat tabs.
+func (c *Chat) Data() res.Chat {
+	data := res.Chat{}
+	if c.activeTab != nil {
+		data.Active = c.activeTab.name
+	}
+	for _, t := range c.tabs {
+		tabData := res.ChatTab{Name: t.name}
+		for _, ch := range game.ChatChannels() {
+			if t.channels[ch] {
+				tabData.Channels = append(tabData.Channels, string(ch))
+			}
+		}
+		data.Tabs = append(data.Tabs, tabData)
+	}
+	return data
+}
+
+// Apply applies specified data on the chat.
+// Default tabs are kept if there are no tabs in the data.
+func (c *Chat) Apply(data res.Chat) {
+	if len(data.Tabs) > 0 {
+		c.setTabs(data.Tabs)
+	}
+	for _, t := range c.tabs {
+		if t.name == data.Active {
+			c.selectTab(t)
+		}
+	}
+}
+
+// setTabs replaces all chat tabs with tabs from specified
+// data and selects the first tab.
+func (c *Chat) setTabs(data []res.ChatTab) {
+	c.tabs = make([]*chatTab, 0)
+	c.activeTab = nil
+	for _, d := range data {
+		err := c.setTab(d)
+		if err != nil {
+			log.Err.Printf("hud: chat: unable to set tab: %s: %v", d.Name, err)
+		}
+	}
+	if len(c.tabs) > 0 {
+		c.selectTab(c.tabs[0])
+	}
+}
+
+// setTab adds chat tab from specified data, or updates
+// channels of the existing tab with the same name.
+func (c *Chat) setTab(data res.ChatTab) error {
+	if len(data.Channels) < 1 {
+		return fmt.Errorf("No channels")
+	}
+	channels := make(map[game.ChatChannel]bool)
+	for _, ch := range data.Channels {
+		if !validChatChannel(game.ChatChannel(ch)) {
+			return fmt.Errorf("Invalid channel: %s", ch)
+		}
+		channels[game.ChatChannel(ch)] = true
+	}
+	for _, t := range c.tabs {
+		if t.name == data.Name {
+			t.channels = channels
+			return nil
+		}
+	}
+	if len(c.tabs) >= chatMaxTabs {
+		return fmt.Errorf("Too many tabs")
+	}
+	buttonParams := mtk.Params{
+		Size:      mtk.SizeMini,
+		FontSize:  mtk.SizeMini,
+		Shape:     mtk.ShapeRectangle,
+		MainColor: accentColor,
+	}
+	tab := chatTab{
+		name:     data.Name,
+		channels: channels,
+		button:   mtk.NewButton(buttonParams),
+	}
+	buttonBG := graphic.Textures["button_green.png"]
+	if buttonBG != nil {
+		bg := pixel.NewSprite(buttonBG, buttonBG.Bounds())
+		tab.button.SetBackground(bg)
+	}
+	tab.button.SetLabel(lang.Text(tab.name))
+	tab.button.SetInfo(tab.info())
+	tab.button.SetOnClickFunc(func(b *mtk.Button) {
+		c.selectTab(&tab)
+	})
+	c.tabs = append(c.tabs, &tab)
+	return nil
+}
+
+// removeTab removes chat tab with specified name.
+// The last tab can't be removed.
+func (c *Chat) removeTab(name string) error {
+	if len(c.tabs) < 2 {
+		return fmt.Errorf("Unable to remove the last tab")
+	}
+	for i, t := range c.tabs {
+		if t.name != name {
+			continue
+		}
+		c.tabs = append(c.tabs[:i], c.tabs[i+1:]...)
+		if c.activeTab == t {
+			c.selectTab(c.tabs[0])
+		}
+		return nil
+	}
+	return fmt.Errorf("Tab not found: %s", name)
+}
+
+// selectTab sets specified tab as the active chat tab.
+func (c *Chat) selectTab(tab *chatTab) {
+	if c.activeTab != nil {
+		c.activeTab.button.SetLabel(lang.Text(c.activeTab.name))
+	}
+	c.activeTab = tab
+	tab.button.SetLabel(fmt.Sprintf("[%s]", lang.Text(tab.name)))
+	c.resetTextbox()
+}
+
 // addObjectMessage adds specified object message to the chat log.
-func (c *Chat) addObjectMessage(objectID string, msg objects.Message) {
+func (c *Chat) addObjectMessage(objectID string, channel game.ChatChannel, msg objects.Message) {
 	chatMsg := Message{
-		author: objectID,
-		time:   msg.Time,
-		text:   fmt.Sprintf("%s\n", msg),
+		author:  objectID,
+		channel: channel,
+		time:    msg.Time,
+		text:    fmt.Sprintf("%s\n", msg),
 	}
 	if !msg.Translated {
 		chatMsg.text = fmt.Sprintf("%s\n", lang.Text(msg.String()))
 	}
-	c.messages = append(c.messages, chatMsg)
+	c.addMessage(chatMsg)
 }
 
 // combatLogger retruns returns object with combat
@@ -274,9 +703,11 @@ func (c *Chat) onChatKeyPressed() {
 		c.Activate(false)
 		return
 	}
-	// Save last input.
+	// Save command input in history.
 	input := c.textedit.Text()
-	c.lastInput = input
+	if chatCommand(input) {
+		c.history.Add(input)
+	}
 	defer c.textedit.Clear()
 	// Execute command.
 	if strings.HasPrefix(input, chatCommandPrefix) {
@@ -308,9 +739,95 @@ func (c *Chat) onChatKeyPressed() {
 		}
 		return
 	}
+	// Configure tabs.
+	if cmd, args, _ := strings.Cut(input, " "); cmd == chatTabPrefix {
+		err := c.executeTabCommand(strings.Fields(args))
+		if err != nil {
+			log.Err.Printf("Unable to configure chat tabs: %v", err)
+		}
+		return
+	}
 	// Echo chat.
-	msg := objects.NewMessage(input, true)
-	c.hud.Game().ActivePlayerChar().AddChatMessage(msg)
+	pc := c.hud.Game().ActivePlayerChar()
+	if pc == nil {
+		return
+	}
+	msg := game.ParseChat(input)
+	if msg.Channel == game.ChatWhisper && (len(msg.Recipient) < 1 || len(msg.Text) < 1) {
+		log.Err.Printf("%s", lang.Text("hud_chat_whisper_invalid"))
+		return
+	}
+	err := pc.Chat(msg)
+	if err != nil {
+		log.Err.Printf("%v", err)
+	}
+}
+
+// chatCommand checks if specified chat input is a command,
+// script or tab command, and not a chat message.
+func chatCommand(input string) bool {
+	cmd, _, _ := strings.Cut(input, " ")
+	return strings.HasPrefix(input, chatCommandPrefix) ||
+		strings.HasPrefix(input, chatScriptPrefix) || cmd == chatTabPrefix
+}
+
+// complete completes the command in the chat input and
+// prints completion candidates if there is more than one.
+func (c *Chat) complete() {
+	text := c.textedit.Text()
+	if !strings.HasPrefix(text, chatCommandPrefix) {
+		return
+	}
+	cmd := strings.TrimPrefix(text, chatCommandPrefix)
+	cmd, candidates := c.completer.Complete(cmd)
+	c.textedit.SetText(chatCommandPrefix + cmd)
+	if len(candidates) > 1 {
+		c.Echo(strings.Join(candidates, " "))
+	}
+}
+
+// completionTargets returns IDs and serials of all visible
+// avatars from the current area, as command targets.
+func (c *Chat) completionTargets() []string {
+	targets := make([]string, 0)
+	if c.hud.camera.area == nil {
+		return targets
+	}
+	for _, a := range c.hud.camera.area.Avatars() {
+		if c.hud.game.VisibleForPlayer(a.Position().X, a.Position().Y) {
+			targets = append(targets, a.ID()+"#"+a.Serial())
+		}
+	}
+	return targets
+}
+
+// matchSearch checks if specified message contains
+// the search box text.
+func (c *Chat) matchSearch(m Message) bool {
+	if len(c.searchText) < 1 {
+		return true
+	}
+	text := strings.ToLower(m.String())
+	return strings.Contains(text, strings.ToLower(c.searchText))
+}
+
+// executeTabCommand handles specified arguments of the chat
+// tab command.
+// 'add [name] [channels...]' adds new tab or sets channels of
+// the existing tab, 'remove [name]' removes tab.
+func (c *Chat) executeTabCommand(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Not enough arguments")
+	}
+	switch args[0] {
+	case "add":
+		tab := res.ChatTab{Name: args[1], Channels: args[2:]}
+		return c.setTab(tab)
+	case "remove":
+		return c.removeTab(args[1])
+	default:
+		return fmt.Errorf("Invalid option: %s", args[0])
+	}
 }
 
 // executeScriptFile executes Ash script from file
@@ -337,3 +854,55 @@ func executeCommand(line string) (int, string, error) {
 	res, out := burn.HandleExpression(cmd)
 	return res, out, nil
 }
+
+// String returns message text with author and
+// channel.
+func (m Message) String() string {
+	switch m.channel {
+	case game.ChatSay, game.ChatSystem, game.ChatCombat:
+		return fmt.Sprintf("%s: %s", lang.Text(m.author), m.text)
+	default:
+		return fmt.Sprintf("[%s] %s: %s", lang.Text("hud_chat_channel_"+string(m.channel)),
+			lang.Text(m.author), m.text)
+	}
+}
+
+// info returns info text for tab button.
+func (t *chatTab) info() string {
+	info := lang.Text("hud_chat_tab_info")
+	for _, ch := range game.ChatChannels() {
+		if t.channels[ch] {
+			info = fmt.Sprintf("%s\n%s", info, lang.Text("hud_chat_channel_"+string(ch)))
+		}
+	}
+	return info
+}
+
+// defaultChatTabs returns data for default chat tabs.
+func defaultChatTabs() []res.ChatTab {
+	all := res.ChatTab{Name: "hud_chat_tab_all"}
+	for _, ch := range game.ChatChannels() {
+		all.Channels = append(all.Channels, string(ch))
+	}
+	chat := res.ChatTab{
+		Name: "hud_chat_tab_chat",
+		Channels: []string{string(game.ChatSay), string(game.ChatParty),
+			string(game.ChatWhisper)},
+	}
+	combat := res.ChatTab{
+		Name:     "hud_chat_tab_combat",
+		Channels: []string{string(game.ChatCombat), string(game.ChatLoot)},
+	}
+	return []res.ChatTab{all, chat, combat}
+}
+
+// validChatChannel checks if specified chat channel is
+// supported.
+func validChatChannel(channel game.ChatChannel) bool {
+	for _, ch := range game.ChatChannels() {
+		if ch == channel {
+			return true
+		}
+	}
+	return false
+}
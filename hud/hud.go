@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"golang.org/x/image/colornames"
 
@@ -56,6 +57,9 @@ var (
 	mainColor   = colornames.Grey
 	secColor    = colornames.Blue
 	accentColor = colornames.Red
+	// Color for slots with items waiting for
+	// the server confirmation.
+	pendingSlotColor = pixel.RGBA{0.4, 0.3, 0.1, 0.5}
 	// Keys.
 	pauseKey  = pixelgl.KeySpace
 	exitKey   = pixelgl.KeyEscape
@@ -71,6 +75,8 @@ type HUD struct {
 	savemenu      *SaveMenu
 	pcFrame       *ObjectFrame
 	tarFrame      *ObjectFrame
+	party         *PartyFrames
+	spectator     *Spectator
 	objectInfo    *ObjectInfo
 	castBar       *CastBar
 	chat          *Chat
@@ -83,9 +89,13 @@ type HUD struct {
 	charinfo      *CharacterWindow
 	trade         *TradeWindow
 	training      *TrainingWindow
+	minimap       *Minimap
+	mapWin        *MapWindow
 	game          *game.Game
 	userFocus     *mtk.Focus
 	msgs          *mtk.MessageQueue
+	connInfo      *mtk.Text
+	netStatsInfo  *mtk.Text
 	layouts       map[string]*Layout
 	defaultLayout *Layout
 	loading       bool
@@ -93,6 +103,8 @@ type HUD struct {
 	loaderr       error
 	onAreaChanged func(a *area.Area)
 	areaScripts   []*ash.Script
+	itemsRollback atomic.Bool
+	pcChangeSub   int64
 }
 
 // New creates new HUD instance.
@@ -105,6 +117,8 @@ func New(win *mtk.Window) *HUD {
 	// Active player & target frames.
 	hud.pcFrame = newObjectFrame(hud)
 	hud.tarFrame = newObjectFrame(hud)
+	hud.party = newPartyFrames(hud)
+	hud.spectator = newSpectator(hud)
 	// Hovered object info window.
 	hud.objectInfo = newObjectInfo(hud)
 	// Cast bar.
@@ -123,9 +137,21 @@ func New(win *mtk.Window) *HUD {
 	hud.charinfo = newCharacterWindow(hud)
 	hud.trade = newTradeWindow(hud)
 	hud.training = newTrainingWindow(hud)
+	hud.minimap = newMinimap(hud)
+	hud.mapWin = newMapWindow(hud)
 	// Messages & focus.
 	hud.userFocus = new(mtk.Focus)
 	hud.msgs = mtk.NewMessageQueue(hud.UserFocus())
+	// Server connection info.
+	connInfoParams := mtk.Params{
+		FontSize: mtk.SizeMedium,
+	}
+	hud.connInfo = mtk.NewText(connInfoParams)
+	// Network statistics.
+	netStatsParams := mtk.Params{
+		FontSize: mtk.SizeSmall,
+	}
+	hud.netStatsInfo = mtk.NewText(netStatsParams)
 	// Layouts.
 	hud.layouts = make(map[string]*Layout)
 	hud.defaultLayout = NewLayout()
@@ -138,12 +164,20 @@ func (hud *HUD) Draw(win *mtk.Window) {
 		hud.loadScreen.Draw(win)
 		return
 	}
-	if hud.Game() == nil || hud.Game().ActivePlayerChar() == nil { // no game or active pc, don't draw
+	if hud.Game() == nil {
+		return
+	}
+	if hud.Game().Spectator() {
+		hud.drawSpectator(win)
+		return
+	}
+	if hud.Game().ActivePlayerChar() == nil { // no active pc, don't draw
 		return
 	}
 	// Elements positions.
 	pcFramePos := mtk.DrawPosTL(win.Bounds(), hud.pcFrame.Size())
 	tarFramePos := mtk.RightOf(hud.pcFrame.DrawArea(), hud.tarFrame.Size(), 0)
+	partyPos := pixel.V(pcFramePos.X, pcFramePos.Y-hud.pcFrame.Size().Y-mtk.ConvSize(10))
 	castBarPos := win.Bounds().Center()
 	barPos := mtk.DrawPosBC(win.Bounds(), hud.bar.Size())
 	chatPos := mtk.DrawPosBL(win.Bounds(), hud.chat.Size())
@@ -158,11 +192,15 @@ func (hud *HUD) Draw(win *mtk.Window) {
 	charinfoPos := win.Bounds().Center()
 	tradePos := win.Bounds().Center()
 	trainPos := win.Bounds().Center()
+	minimapPos := mtk.DrawPosTR(win.Bounds(), hud.minimap.Size())
+	mapWinPos := win.Bounds().Center()
 	// Draw elements.
 	hud.camera.Draw(win)
 	hud.bar.Draw(win, mtk.Matrix().Moved(barPos))
 	hud.chat.Draw(win, mtk.Matrix().Moved(chatPos))
 	hud.pcFrame.Draw(win, mtk.Matrix().Moved(pcFramePos))
+	hud.party.Draw(win, mtk.Matrix().Moved(partyPos))
+	hud.minimap.Draw(win, mtk.Matrix().Moved(minimapPos))
 	if len(hud.Game().ActivePlayerChar().Targets()) > 0 {
 		hud.tarFrame.Draw(win, mtk.Matrix().Moved(tarFramePos))
 	}
@@ -196,6 +234,9 @@ func (hud *HUD) Draw(win *mtk.Window) {
 	if hud.training.Opened() {
 		hud.training.Draw(win, mtk.Matrix().Moved(trainPos))
 	}
+	if hud.mapWin.Opened() {
+		hud.mapWin.Draw(win, mtk.Matrix().Moved(mapWinPos))
+	}
 	if hud.objectInfo.Opened() {
 		hud.objectInfo.Draw(win)
 	}
@@ -205,11 +246,55 @@ func (hud *HUD) Draw(win *mtk.Window) {
 	if hud.menu.Opened() {
 		hud.menu.Draw(win, mtk.Matrix().Moved(menuPos))
 	}
+	// Server connection info.
+	if hud.connLost() {
+		connInfoPos := mtk.DrawPosTC(win.Bounds(), hud.connInfo.Size())
+		hud.connInfo.Draw(win, mtk.Matrix().Moved(connInfoPos))
+	}
+	hud.drawNetStats(win)
 	// Messages.
 	msgPos := win.Bounds().Center()
 	hud.msgs.Draw(win, mtk.Matrix().Moved(msgPos))
 }
 
+// drawSpectator draws HUD elements for spectator mode.
+func (hud *HUD) drawSpectator(win *mtk.Window) {
+	if hud.camera.Area() == nil {
+		return
+	}
+	spectatorPos := mtk.DrawPosTL(win.Bounds(), hud.spectator.info.Size())
+	chatPos := mtk.DrawPosBL(win.Bounds(), hud.chat.Size())
+	menuPos := win.Bounds().Center()
+	hud.camera.Draw(win)
+	hud.chat.Draw(win, mtk.Matrix().Moved(chatPos))
+	hud.spectator.Draw(win, mtk.Matrix().Moved(spectatorPos))
+	if hud.objectInfo.Opened() {
+		hud.objectInfo.Draw(win)
+	}
+	if hud.menu.Opened() {
+		hud.menu.Draw(win, mtk.Matrix().Moved(menuPos))
+	}
+	if hud.connLost() {
+		connInfoPos := mtk.DrawPosTC(win.Bounds(), hud.connInfo.Size())
+		hud.connInfo.Draw(win, mtk.Matrix().Moved(connInfoPos))
+	}
+	hud.drawNetStats(win)
+	msgPos := win.Bounds().Center()
+	hud.msgs.Draw(win, mtk.Matrix().Moved(msgPos))
+}
+
+// updateSpectator updates HUD elements for spectator mode.
+// All action, inventory and trade elements are disabled.
+func (hud *HUD) updateSpectator(win *mtk.Window) {
+	hud.loadScreen.Update(win)
+	hud.spectator.Update(win)
+	hud.camera.Update(win)
+	hud.chat.Update(win)
+	hud.objectInfo.Update(win)
+	hud.menu.Update(win)
+	hud.msgs.Update(win)
+}
+
 // Update updated HUD elements.
 func (hud *HUD) Update(win *mtk.Window) {
 	// HUD state.
@@ -218,18 +303,38 @@ func (hud *HUD) Update(win *mtk.Window) {
 		hud.Exit()
 		return
 	}
-	if hud.Game() == nil || hud.Game().ActivePlayerChar() == nil { // no game or active pc, exit
+	if hud.Game() == nil {
+		log.Err.Printf("HUD: no game")
+		hud.Exit()
+		return
+	}
+	if !hud.Game().Spectator() && hud.Game().ActivePlayerChar() == nil { // no active pc, exit
 		log.Err.Printf("HUD: no player characters")
 		hud.Exit()
 		return
 	}
+	// Server connection info.
+	if hud.connLost() {
+		hud.connInfo.SetText(hud.Game().Server().State().Info())
+	}
+	if config.Debug && hud.Game().Server() != nil {
+		hud.netStatsInfo.SetText(hud.Game().Server().Stats().String())
+	}
+	if hud.Game().Spectator() {
+		hud.updateSpectator(win)
+		return
+	}
+	// Refresh item windows after rolled back item operation.
+	if hud.itemsRollback.Swap(false) {
+		hud.refreshItems()
+	}
 	// Handle area change.
 	hud.updateCurrentArea()
 	// Toggle game pause.
 	if !hud.Chat().Activated() && win.JustPressed(pauseKey) {
 		hud.Game().SetPause(!hud.Game().Pause())
 	}
-	if hud.game.ActivePlayerChar() != nil && win.JustPressed(targetKey) {
+	if !hud.Chat().Activated() && hud.game.ActivePlayerChar() != nil && win.JustPressed(targetKey) {
 		hud.targetNearObject()
 	}
 	// Put PC target into target frame.
@@ -246,6 +351,7 @@ func (hud *HUD) Update(win *mtk.Window) {
 	hud.bar.Update(win)
 	hud.chat.Update(win)
 	hud.pcFrame.Update(win)
+	hud.party.Update(win)
 	hud.tarFrame.Update(win)
 	hud.castBar.Update(win)
 	hud.objectInfo.Update(win)
@@ -260,6 +366,8 @@ func (hud *HUD) Update(win *mtk.Window) {
 	hud.charinfo.Update(win)
 	hud.trade.Update(win)
 	hud.training.Update(win)
+	hud.minimap.Update(win)
+	hud.mapWin.Update(win)
 	hud.msgs.Update(win)
 }
 
@@ -274,6 +382,11 @@ func (hud *HUD) SetActiveChar(char *game.Character) {
 	hud.Reload()
 }
 
+// Spectator returns HUD spectator panel.
+func (hud *HUD) Spectator() *Spectator {
+	return hud.spectator
+}
+
 // Exit sends exit request to HUD.
 func (hud *HUD) Exit() {
 	hud.exiting = true
@@ -348,8 +461,12 @@ func (hud *HUD) Reload() {
 
 // SetGame sets HUD game.
 func (hud *HUD) SetGame(g *game.Game) {
+	if hud.game != nil {
+		hud.game.Events().Unsubscribe(hud.pcChangeSub)
+	}
 	hud.game = g
-	hud.game.SetOnPlayerCharChangeFunc(hud.SetActiveChar)
+	hud.pcChangeSub = game.SubscribeTo(g.Events(), hud.onActiveCharChanged)
+	hud.game.SetOnPendingRollbackFunc(hud.onPendingRollback)
 }
 
 // PCAvatar return avatar for player current character.
@@ -358,6 +475,9 @@ func (hud *HUD) PCAvatar() *object.Avatar {
 		return nil
 	}
 	pc := hud.game.ActivePlayerChar()
+	if pc == nil {
+		return nil
+	}
 	for _, av := range hud.camera.area.Avatars() {
 		if av.ID() == pc.ID() && av.Serial() == pc.Serial() {
 			return av
@@ -393,6 +513,24 @@ func (hud *HUD) ChangeArea(area *area.Area) error {
 	return nil
 }
 
+// ReloadArea reloads current HUD area from the current
+// game chapter, e.g. after the chapter change.
+func (hud *HUD) ReloadArea() error {
+	if hud.Game().Spectator() {
+		hud.spectator.SetFollow(nil)
+		startArea := hud.Game().Chapter().Area(hud.Game().Chapter().Conf().StartArea)
+		if startArea == nil {
+			return fmt.Errorf("chapter start area not found")
+		}
+		return hud.ChangeArea(startArea)
+	}
+	pcArea := hud.Game().Chapter().ObjectArea(hud.Game().ActivePlayerChar())
+	if pcArea == nil {
+		return fmt.Errorf("active player character area not found")
+	}
+	return hud.ChangeArea(pcArea)
+}
+
 // Data returns data struct for HUD.
 func (hud *HUD) Data() res.HUDData {
 	var data res.HUDData
@@ -416,6 +554,10 @@ func (hud *HUD) Data() res.HUDData {
 	// Camera XY position.
 	data.Camera.X = hud.Camera().Position().X
 	data.Camera.Y = hud.Camera().Position().Y
+	// Party.
+	data.Party = hud.party.Data()
+	// Chat.
+	data.Chat = hud.chat.Data()
 	return data
 }
 
@@ -443,6 +585,10 @@ func (hud *HUD) Apply(data res.HUDData) error {
 	}
 	// Camera position.
 	hud.camera.SetPosition(pixel.V(data.Camera.X, data.Camera.Y))
+	// Party.
+	hud.party.Apply(data.Party)
+	// Chat.
+	hud.chat.Apply(data.Chat)
 	// Reload UI.
 	hud.Reload()
 	return nil
@@ -500,12 +646,30 @@ func (hud *HUD) updateCurrentArea() {
 	}
 }
 
+// drawNetStats draws statistics of the game server
+// connection in debug mode.
+func (hud *HUD) drawNetStats(win *mtk.Window) {
+	if !config.Debug || hud.Game().Server() == nil {
+		return
+	}
+	netStatsPos := mtk.DrawPosTR(win.Bounds(), hud.netStatsInfo.Size())
+	hud.netStatsInfo.Draw(win, mtk.Matrix().Moved(netStatsPos))
+}
+
+// connLost checks if connection to the game server
+// was lost.
+func (hud *HUD) connLost() bool {
+	return hud.Game().Server() != nil && hud.Game().Server().State() != game.Connected
+}
+
 // containsPos checks if specified position is contained
 // by any HUD element(except camera).
 func (hud *HUD) containsPos(pos pixel.Vec) bool {
 	return hud.bar.DrawArea().Contains(pos) ||
 		hud.chat.DrawArea().Contains(pos) ||
 		hud.pcFrame.DrawArea().Contains(pos) ||
+		hud.party.DrawArea().Contains(pos) ||
+		hud.minimap.DrawArea().Contains(pos) ||
 		(hud.inv.Opened() && hud.inv.DrawArea().Contains(pos)) ||
 		(hud.menu.Opened() && hud.menu.DrawArea().Contains(pos)) ||
 		(hud.savemenu.Opened() && hud.savemenu.DrawArea().Contains(pos)) ||
@@ -516,7 +680,8 @@ func (hud *HUD) containsPos(pos pixel.Vec) bool {
 		(hud.crafting.Opened() && hud.crafting.DrawArea().Contains(pos)) ||
 		(hud.trade.Opened() && hud.trade.DrawArea().Contains(pos)) ||
 		(hud.training.Opened() && hud.training.DrawArea().Contains(pos)) ||
-		(hud.charinfo.Opened() && hud.charinfo.DrawArea().Contains(pos))
+		(hud.charinfo.Opened() && hud.charinfo.DrawArea().Contains(pos)) ||
+		(hud.mapWin.Opened() && hud.mapWin.DrawArea().Contains(pos))
 }
 
 // menuOpen checks if any HUD menu is open.
@@ -526,5 +691,15 @@ func (hud *HUD) menuOpen() bool {
 		hud.journal.Opened() || hud.loot.Opened() ||
 		hud.menu.Opened() || hud.savemenu.Opened() ||
 		hud.trade.Opened() || hud.training.Opened() ||
-		hud.skills.Opened()
+		hud.skills.Opened() || hud.mapWin.Opened()
+}
+
+// Triggered after pending item operation was rolled back.
+func (hud *HUD) onPendingRollback(op *game.PendingOp) {
+	hud.itemsRollback.Store(true)
+}
+
+// Triggered after active player character change.
+func (hud *HUD) onActiveCharChanged(e game.ActivePlayerCharChangedEvent) {
+	hud.SetActiveChar(e.Char)
 }
@@ -1,7 +1,7 @@
 /*
  * hudutils.go
  *
- * Copyright 2019-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -64,6 +64,33 @@ func (hud *HUD) insertSlotItem(it *object.ItemGraphic, s *mtk.Slot) {
 	}
 }
 
+// slotPending checks if specified slot contains items
+// waiting for the server confirmation.
+func (hud *HUD) slotPending(s *mtk.Slot) bool {
+	for _, v := range s.Values() {
+		ig, ok := v.(*object.ItemGraphic)
+		if ok && hud.Game().ItemPending(ig.Item) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshItems refreshes content of all opened windows
+// with items.
+func (hud *HUD) refreshItems() {
+	if hud.inv.Opened() {
+		hud.inv.refresh()
+	}
+	if hud.loot.Opened() && hud.loot.target != nil {
+		hud.loot.SetTarget(hud.loot.target)
+	}
+	if hud.trade.Opened() {
+		hud.trade.Hide()
+		hud.trade.Show()
+	}
+}
+
 // itemInfo returns formated string with
 // informations about specified item.
 func (hud *HUD) itemInfo(it item.Item) string {
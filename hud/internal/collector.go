@@ -0,0 +1,145 @@
+/*
+ * collector.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package internal
+
+import (
+	"sync"
+
+	"github.com/isangeles/flame/objects"
+)
+
+// Struct for message collected from source log.
+type CollectedMessage struct {
+	Source  string
+	Log     int
+	Message objects.Message
+}
+
+// Collector fans in messages from logs of multiple
+// sources into a single channel.
+// Each log is listened by a separate goroutine blocked
+// until the new message or the source removal.
+type Collector struct {
+	mutex   sync.Mutex
+	wait    sync.WaitGroup
+	sources map[string]chan struct{}
+	out     chan CollectedMessage
+	closed  bool
+}
+
+// NewCollector creates new log messages collector.
+func NewCollector() *Collector {
+	c := Collector{
+		sources: make(map[string]chan struct{}),
+		out:     make(chan CollectedMessage, 64),
+	}
+	return &c
+}
+
+// Messages returns channel with messages collected from
+// all sources.
+// Channel is closed after the collector is closed.
+func (c *Collector) Messages() <-chan CollectedMessage {
+	return c.out
+}
+
+// Sync starts collecting messages from logs of specified
+// sources and stops collecting from all sources not present
+// in specified map.
+// Sources are identified by the map keys, logs by the index
+// in the logs slice.
+// Does nothing if the collector is closed.
+func (c *Collector) Sync(sources map[string][]*objects.Log) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return
+	}
+	c.stopSources(sources)
+	for key, logs := range sources {
+		if _, ok := c.sources[key]; ok {
+			continue
+		}
+		stop := make(chan struct{})
+		c.sources[key] = stop
+		for i, l := range logs {
+			c.wait.Add(1)
+			go c.collect(key, i, l, stop)
+		}
+	}
+}
+
+// Sources returns number of sources.
+func (c *Collector) Sources() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.sources)
+}
+
+// Close stops collecting messages from all sources and
+// closes the messages channel, after all sources stop.
+// Closing already closed collector does nothing.
+func (c *Collector) Close() {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return
+	}
+	c.closed = true
+	c.stopSources(nil)
+	c.mutex.Unlock()
+	c.wait.Wait()
+	close(c.out)
+}
+
+// stopSources stops collecting from all sources not present
+// in specified map.
+// Collector mutex must be locked by the caller.
+func (c *Collector) stopSources(sources map[string][]*objects.Log) {
+	for key, stop := range c.sources {
+		if _, ok := sources[key]; !ok {
+			close(stop)
+			delete(c.sources, key)
+		}
+	}
+}
+
+// collect passes messages from specified log to the
+// output channel until the stop channel is closed.
+func (c *Collector) collect(source string, id int, log *objects.Log, stop chan struct{}) {
+	defer c.wait.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		case msg := <-log.Channel():
+			collected := CollectedMessage{source, id, msg}
+			select {
+			case c.out <- collected:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * collector_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame/objects"
+)
+
+// TestCollector tests collecting messages from
+// multiple sources.
+func TestCollector(t *testing.T) {
+	sources := testSources(2)
+	c := NewCollector()
+	defer c.Close()
+	c.Sync(sources)
+	sources["source1"][1].Channel() <- objects.NewMessage("test", true)
+	select {
+	case msg := <-c.Messages():
+		if msg.Source != "source1" || msg.Log != 1 || msg.Message.String() != "test" {
+			t.Errorf("Invalid message: %v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Message not collected")
+	}
+	delete(sources, "source0")
+	c.Sync(sources)
+	if c.Sources() != 1 {
+		t.Errorf("Invalid number of sources: %d != 1", c.Sources())
+	}
+}
+
+// TestCollectorClose tests closing the messages channel
+// after closing the collector.
+func TestCollectorClose(t *testing.T) {
+	sources := testSources(2)
+	c := NewCollector()
+	c.Sync(sources)
+	done := make(chan struct{})
+	go func() {
+		for range c.Messages() {
+		}
+		close(done)
+	}()
+	sources["source0"][0].Channel() <- objects.NewMessage("test", true)
+	c.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Messages channel not closed")
+	}
+	c.Close()
+	c.Sync(sources)
+	if c.Sources() != 0 {
+		t.Errorf("Invalid number of sources after close: %d != 0", c.Sources())
+	}
+}
+
+// BenchmarkCollector benchmarks collecting messages
+// from hundreds of sources into the ring buffer.
+func BenchmarkCollector(b *testing.B) {
+	for _, n := range []int{100, 500} {
+		b.Run(fmt.Sprintf("sources-%d", n), func(b *testing.B) {
+			sources := testSources(n)
+			c := NewCollector()
+			defer c.Close()
+			c.Sync(sources)
+			ring := NewRing[CollectedMessage](1000)
+			done := make(chan struct{})
+			go func() {
+				for i := 0; i < b.N; i++ {
+					ring.Add(<-c.Messages())
+				}
+				close(done)
+			}()
+			msg := objects.NewMessage("test", true)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logs := sources[fmt.Sprintf("source%d", i%n)]
+				logs[i%len(logs)].Channel() <- msg
+			}
+			<-done
+		})
+	}
+}
+
+// testSources creates specified number of test sources,
+// each with three logs.
+func testSources(n int) map[string][]*objects.Log {
+	sources := make(map[string][]*objects.Log)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("source%d", i)
+		sources[key] = []*objects.Log{objects.NewLog(), objects.NewLog(), objects.NewLog()}
+	}
+	return sources
+}
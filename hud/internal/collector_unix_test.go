@@ -0,0 +1,60 @@
+//go:build unix
+
+/*
+ * collector_unix_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package internal
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+// BenchmarkCollectorIdle benchmarks CPU usage of the collector
+// listening to hundreds of sources without new messages.
+// Reports CPU time used by the process per second.
+func BenchmarkCollectorIdle(b *testing.B) {
+	c := NewCollector()
+	defer c.Close()
+	c.Sync(testSources(500))
+	var cpu, wall time.Duration
+	for i := 0; i < b.N; i++ {
+		start, cpuStart := time.Now(), cpuTime(b)
+		time.Sleep(10 * time.Millisecond)
+		cpu += cpuTime(b) - cpuStart
+		wall += time.Since(start)
+	}
+	b.ReportMetric(cpu.Seconds()/wall.Seconds(), "cpu-s/s")
+}
+
+// cpuTime returns user and system CPU time used by the
+// process.
+func cpuTime(b *testing.B) time.Duration {
+	var usage syscall.Rusage
+	err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage)
+	if err != nil {
+		b.Fatalf("Unable to get resource usage: %v", err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
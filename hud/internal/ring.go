@@ -0,0 +1,83 @@
+/*
+ * ring.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package internal
+
+import (
+	"sync"
+)
+
+// Ring buffer with fixed capacity, safe for concurrent use.
+// Each added value gets the next sequence number, so readers
+// can retrieve only values added since the last read.
+type Ring[T any] struct {
+	mutex  sync.RWMutex
+	values []T
+	next   uint64
+}
+
+// NewRing creates new ring buffer with specified capacity.
+func NewRing[T any](size int) *Ring[T] {
+	r := Ring[T]{values: make([]T, size)}
+	return &r
+}
+
+// Add adds specified value to the buffer, the oldest value
+// is overwritten if the buffer is full.
+func (r *Ring[T]) Add(v T) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.values[r.next%uint64(len(r.values))] = v
+	r.next++
+}
+
+// Since returns values with sequence number equal or greater
+// than specified one, from the oldest to the newest value,
+// and sequence number of the next value.
+// Values already overwritten in the buffer are skipped.
+func (r *Ring[T]) Since(seq uint64) ([]T, uint64) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	size := uint64(len(r.values))
+	if r.next > size && seq < r.next-size {
+		seq = r.next - size
+	}
+	if seq >= r.next {
+		return nil, r.next
+	}
+	values := make([]T, 0, r.next-seq)
+	for i := seq; i < r.next; i++ {
+		values = append(values, r.values[i%size])
+	}
+	return values, r.next
+}
+
+// Len returns number of values in the buffer.
+func (r *Ring[T]) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.next < uint64(len(r.values)) {
+		return int(r.next)
+	}
+	return len(r.values)
+}
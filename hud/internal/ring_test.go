@@ -0,0 +1,57 @@
+/*
+ * ring_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package internal
+
+import (
+	"testing"
+)
+
+// TestRing tests adding and retrieving values from
+// the ring buffer.
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	values, next := r.Since(0)
+	if len(values) != 0 || next != 0 {
+		t.Errorf("Invalid values of empty buffer: %v %d", values, next)
+	}
+	r.Add(1)
+	r.Add(2)
+	values, next = r.Since(0)
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 || next != 2 {
+		t.Errorf("Invalid values: %v %d", values, next)
+	}
+	r.Add(3)
+	r.Add(4)
+	values, next = r.Since(next)
+	if len(values) != 2 || values[0] != 3 || values[1] != 4 || next != 4 {
+		t.Errorf("Invalid new values: %v %d", values, next)
+	}
+	values, _ = r.Since(0)
+	if len(values) != 3 || values[0] != 2 || values[2] != 4 {
+		t.Errorf("Invalid values after overwrite: %v", values)
+	}
+	if r.Len() != 3 {
+		t.Errorf("Invalid length: %d != 3", r.Len())
+	}
+}
@@ -1,7 +1,7 @@
 /*
  * inventorymenu.go
  *
- * Copyright 2019-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -159,6 +159,7 @@ func (im *InventoryMenu) Update(win *mtk.Window) {
 	}
 	// Elements update.
 	if im.Opened() {
+		im.updateSlotsColor()
 		im.slots.Update(win)
 		im.closeButton.Update(win)
 	}
@@ -466,6 +467,29 @@ func (im *InventoryMenu) onSlotSpecialLeftClicked(s *mtk.Slot) {
 	s.Drag(true)
 }
 
+// updateSlotsColor sets proper colors for inventory slots
+// with equipped and pending items.
+func (im *InventoryMenu) updateSlotsColor() {
+	for _, s := range im.slots.Slots() {
+		if im.hud.slotPending(s) {
+			s.SetColor(pendingSlotColor)
+			continue
+		}
+		s.SetColor(invSlotColor)
+		if len(s.Values()) < 1 {
+			continue
+		}
+		ig, ok := s.Values()[0].(*object.ItemGraphic)
+		if !ok {
+			continue
+		}
+		eqIt, ok := ig.Item.(item.Equiper)
+		if ok && im.hud.PCAvatar() != nil && im.hud.PCAvatar().Equipment().Equiped(eqIt) {
+			s.SetColor(invSlotEqColor)
+		}
+	}
+}
+
 // resetSlots resets all inventory slots to the initial state.
 func (im *InventoryMenu) resetSlots() {
 	for _, s := range im.slots.Slots() {
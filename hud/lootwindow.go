@@ -1,7 +1,7 @@
 /*
  * lootwindow.go
  *
- * Copyright 2019-2024 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -24,6 +24,8 @@
 package hud
 
 import (
+	"fmt"
+
 	"github.com/gopxl/pixel"
 	"github.com/gopxl/pixel/imdraw"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/isangeles/mtk"
 
 	"github.com/isangeles/mural/data/res/graphic"
+	"github.com/isangeles/mural/game"
 	"github.com/isangeles/mural/log"
 	"github.com/isangeles/mural/object"
 )
@@ -141,6 +144,13 @@ func (lw *LootWindow) Update(win *mtk.Window) {
 	}
 	// Elements.
 	if lw.Opened() {
+		for _, s := range lw.slots.Slots() {
+			if lw.hud.slotPending(s) {
+				s.SetColor(pendingSlotColor)
+				continue
+			}
+			s.SetColor(lootSlotColor)
+		}
 		lw.slots.Update(win)
 		lw.closeButton.Update(win)
 	}
@@ -259,5 +269,8 @@ func (lw *LootWindow) onSlotLeftClicked(s *mtk.Slot) {
 				err)
 			continue
 		}
+		pc := lw.hud.Game().ActivePlayerChar()
+		msg := fmt.Sprintf("%s: %s", lang.Text("hud_loot_item_msg"), lang.Text(ig.ID()))
+		lw.hud.Chat().Add(game.ChatLoot, pc.ID(), msg)
 	}
 }
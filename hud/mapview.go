@@ -0,0 +1,279 @@
+/*
+ * mapview.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+
+	"golang.org/x/image/colornames"
+
+	"github.com/isangeles/flame/character"
+
+	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/stone"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/object"
+)
+
+var (
+	mapBGColor       = pixel.RGBA{0.1, 0.1, 0.1, 0.9}
+	mapPCColor       = colornames.Yellow
+	mapPartyColor    = colornames.Cyan
+	mapLootColor     = colornames.Gold
+	mapHostileColor  = colornames.Red
+	mapFriendlyColor = colornames.Green
+	mapNeutralColor  = colornames.Lightgrey
+)
+
+const (
+	// Max size of the canvas with rendered map, bigger maps
+	// are rendered in lower scale.
+	mapCacheMaxSize = 4096.0
+)
+
+// Struct for scaled down view of the current area map,
+// with markers for avatars and 'Fog Of War' effect.
+type mapView struct {
+	hud        *HUD
+	canvas     *pixelgl.Canvas
+	draw       *imdraw.IMDraw
+	drawArea   pixel.Rect
+	center     pixel.Vec
+	scale      float64
+	markerSize float64
+	// Map rendered for the current scale.
+	mapCanvas     *pixelgl.Canvas
+	mapCanvasMap  *stone.Map
+	mapViewScale  float64
+	mapCacheScale float64
+}
+
+// newMapView creates new map view with specified size
+// and scale.
+func newMapView(hud *HUD, size pixel.Vec, scale float64) *mapView {
+	mv := new(mapView)
+	mv.hud = hud
+	mv.canvas = pixelgl.NewCanvas(pixel.R(0, 0, size.X, size.Y))
+	mv.draw = imdraw.New(nil)
+	mv.scale = scale
+	mv.markerSize = mtk.ConvSize(3)
+	return mv
+}
+
+// Draw draws map view.
+func (mv *mapView) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	mv.drawArea = mtk.MatrixToDrawArea(matrix, mv.Size())
+	mv.canvas.Clear(mapBGColor)
+	area := mv.hud.camera.Area()
+	if area != nil && area.Map() != nil {
+		mv.drawMap(area.Map())
+		mv.draw.Clear()
+		if config.MapFOW {
+			mv.drawFOW(area)
+		}
+		mv.drawMarkers(area)
+		mv.draw.Draw(mv.canvas)
+	}
+	// Canvas size is already converted to the UI scale.
+	mv.canvas.Draw(win, pixel.IM.Moved(pixel.V(matrix[4], matrix[5])))
+}
+
+// SetSize sets view size.
+func (mv *mapView) SetSize(size pixel.Vec) {
+	if size == mv.Size() {
+		return
+	}
+	mv.canvas.SetBounds(pixel.R(0, 0, size.X, size.Y))
+}
+
+// Size returns view size.
+func (mv *mapView) Size() pixel.Vec {
+	return mv.canvas.Bounds().Size()
+}
+
+// DrawArea returns current view draw area.
+func (mv *mapView) DrawArea() pixel.Rect {
+	return mv.drawArea
+}
+
+// AreaPos translates specified window position to
+// the area position.
+func (mv *mapView) AreaPos(pos pixel.Vec) pixel.Vec {
+	viewPos := pos.Sub(mv.drawArea.Min)
+	return viewPos.Add(mv.offset()).Scaled(1 / mv.scale)
+}
+
+// moveTo moves selected party members to the area
+// position under specified window position.
+func (mv *mapView) moveTo(pos pixel.Vec) {
+	area := mv.hud.camera.Area()
+	if area == nil || mv.hud.Game().Pause() {
+		return
+	}
+	destPos := mv.AreaPos(pos)
+	if area.PassablePosition(destPos) {
+		mv.hud.Game().MoveGroup(mv.hud.party.Selected(), destPos.X, destPos.Y)
+	}
+}
+
+// offset returns offset of the map draw position that
+// puts the view center in the middle of the view.
+func (mv *mapView) offset() pixel.Vec {
+	return mv.center.Scaled(mv.scale).Sub(mv.Size().Scaled(0.5))
+}
+
+// viewPos translates specified area position to
+// the view position.
+func (mv *mapView) viewPos(pos pixel.Vec) pixel.Vec {
+	return pos.Scaled(mv.scale).Sub(mv.offset())
+}
+
+// visibleRect returns rectangle of the area visible in
+// the view.
+func (mv *mapView) visibleRect() pixel.Rect {
+	first := mv.offset().Scaled(1 / mv.scale)
+	last := mv.offset().Add(mv.Size()).Scaled(1 / mv.scale)
+	return pixel.R(first.X, first.Y, last.X, last.Y)
+}
+
+// drawMap draws specified map on the view canvas.
+// Map is rendered on the map canvas only after the map
+// or the view scale changes.
+func (mv *mapView) drawMap(areaMap *stone.Map) {
+	if mv.mapCanvas == nil || mv.mapCanvasMap != areaMap || mv.mapViewScale != mv.scale {
+		mv.renderMap(areaMap)
+	}
+	drawScale := mv.scale / mv.mapCacheScale
+	pos := mv.mapCanvas.Bounds().Center().Scaled(drawScale).Sub(mv.offset())
+	mv.mapCanvas.Draw(mv.canvas, pixel.IM.Scaled(pixel.ZV, drawScale).Moved(pos))
+}
+
+// renderMap renders specified map scaled to the view scale
+// on the map canvas.
+// Map canvas size is limited by the max cache size, bigger
+// maps are rendered in lower scale and scaled up while drawn.
+func (mv *mapView) renderMap(areaMap *stone.Map) {
+	mapSize := areaMap.Size()
+	scale := mv.scale
+	if maxSize := math.Max(mapSize.X, mapSize.Y) * scale; maxSize > mapCacheMaxSize {
+		scale *= mapCacheMaxSize / maxSize
+	}
+	// Margin for tiles drawn over the map edges.
+	margin := areaMap.TileSize().Scaled(scale)
+	bounds := pixel.R(-margin.X, -margin.Y, mapSize.X*scale+margin.X,
+		mapSize.Y*scale+margin.Y)
+	if mv.mapCanvas == nil {
+		mv.mapCanvas = pixelgl.NewCanvas(bounds)
+	} else {
+		mv.mapCanvas.SetBounds(bounds)
+	}
+	mv.mapCanvas.Clear(pixel.Alpha(0))
+	areaMap.Draw(mv.mapCanvas, pixel.IM.Scaled(pixel.ZV, scale))
+	mv.mapCanvasMap = areaMap
+	mv.mapViewScale = mv.scale
+	mv.mapCacheScale = scale
+}
+
+// drawFOW draws 'Fog Of War' effect over map tiles
+// not visible for the player.
+// Only tiles visible in the view are covered.
+func (mv *mapView) drawFOW(area *object.Area) {
+	tileSize := area.Map().TileSize()
+	mapSize := area.Map().Size()
+	visible := mv.visibleRect()
+	minX := math.Max(0, math.Floor(visible.Min.X/tileSize.X)*tileSize.X)
+	minY := math.Max(0, math.Floor(visible.Min.Y/tileSize.Y)*tileSize.Y)
+	maxX := math.Min(mapSize.X, visible.Max.X)
+	maxY := math.Min(mapSize.Y, visible.Max.Y)
+	mv.draw.Color = FOWColor
+	for h := minY; h < maxY; h += tileSize.Y {
+		for w := minX; w < maxX; w += tileSize.X {
+			if mv.hud.Game().VisibleForPlayer(w, h) {
+				continue
+			}
+			tileMin := mv.viewPos(pixel.V(w, h))
+			mv.draw.Push(tileMin)
+			mv.draw.Push(tileMin.Add(tileSize.Scaled(mv.scale)))
+			mv.draw.Rectangle(0)
+		}
+	}
+}
+
+// drawMarkers draws markers for all avatars in specified
+// area, visible in the view.
+// Avatars outside the player sight are not marked, except
+// the party members. Active player character is marked
+// last, over other markers.
+func (mv *mapView) drawMarkers(area *object.Area) {
+	pc := mv.hud.PCAvatar()
+	margin := mv.markerSize / mv.scale
+	visible := mv.visibleRect()
+	visible = pixel.R(visible.Min.X-margin, visible.Min.Y-margin,
+		visible.Max.X+margin, visible.Max.Y+margin)
+	for _, av := range area.Avatars() {
+		if av == pc || !visible.Contains(av.Position()) {
+			continue
+		}
+		party := mv.hud.playerObject(av.ID(), av.Serial())
+		if !party && !mv.hud.Game().VisibleForPlayer(av.Position().X, av.Position().Y) {
+			continue
+		}
+		mv.drawMarker(av.Position(), mv.markerColor(av, pc, party))
+	}
+	if pc != nil {
+		mv.drawMarker(pc.Position(), mapPCColor)
+	}
+}
+
+// drawMarker draws marker with specified color on
+// specified area position.
+func (mv *mapView) drawMarker(pos pixel.Vec, col color.Color) {
+	mv.draw.Color = col
+	mv.draw.Push(mv.viewPos(pos))
+	mv.draw.Circle(mv.markerSize, 0)
+}
+
+// markerColor returns color of the map marker for specified
+// avatar.
+func (mv *mapView) markerColor(av, pc *object.Avatar, party bool) color.Color {
+	switch {
+	case party:
+		return mapPartyColor
+	case !av.Live() || av.OpenLoot():
+		return mapLootColor
+	case pc != nil && av.AttitudeFor(pc) == character.Hostile:
+		return mapHostileColor
+	case pc != nil && av.AttitudeFor(pc) == character.Friendly:
+		return mapFriendlyColor
+	default:
+		return mapNeutralColor
+	}
+}
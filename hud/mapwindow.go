@@ -0,0 +1,239 @@
+/*
+ * mapwindow.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/mural/data/res/graphic"
+)
+
+var (
+	mapKey        = pixelgl.KeyM
+	mapZoomInKey  = pixelgl.KeyEqual
+	mapZoomOutKey = pixelgl.KeyMinus
+)
+
+const (
+	mapMinScale = 0.05
+	mapMaxScale = 2.0
+	mapZoomStep = 1.25
+	// Distance in pixels of the map view move
+	// on each frame with pressed pan key.
+	mapPanStep = 10
+)
+
+// Struct for HUD map window.
+type MapWindow struct {
+	hud         *HUD
+	drawArea    pixel.Rect
+	size        pixel.Vec
+	titleText   *mtk.Text
+	closeButton *mtk.Button
+	opened      bool
+	view        *mapView
+}
+
+// newMapWindow creates new map window for HUD.
+func newMapWindow(hud *HUD) *MapWindow {
+	mw := new(MapWindow)
+	mw.hud = hud
+	// Title.
+	titleParams := mtk.Params{
+		FontSize: mtk.SizeSmall,
+	}
+	mw.titleText = mtk.NewText(titleParams)
+	mw.titleText.SetText(lang.Text("hud_map_title"))
+	// Buttons.
+	buttonParams := mtk.Params{
+		Size:      mtk.SizeMedium,
+		Shape:     mtk.ShapeSquare,
+		MainColor: accentColor,
+	}
+	mw.closeButton = mtk.NewButton(buttonParams)
+	closeButtonBG := graphic.Textures["closebutton1.png"]
+	if closeButtonBG != nil {
+		closeBG := pixel.NewSprite(closeButtonBG,
+			closeButtonBG.Bounds())
+		mw.closeButton.SetBackground(closeBG)
+	}
+	mw.closeButton.SetOnClickFunc(mw.onCloseButtonClicked)
+	// Map view.
+	mw.size = mtk.ConvVec(pixel.V(800, 600))
+	mw.view = newMapView(hud, mw.viewSize(), 1)
+	mw.view.markerSize = mtk.ConvSize(5)
+	return mw
+}
+
+// Draw draws window.
+func (mw *MapWindow) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	// Draw area.
+	mw.drawArea = mtk.MatrixToDrawArea(matrix, mw.Size())
+	// Background.
+	mtk.DrawRect(win, mw.DrawArea(), mapBGColor)
+	// Title.
+	titleTextMove := pixel.V(0, mw.Size().Y/2-mtk.ConvSize(25))
+	mw.titleText.Draw(win, matrix.Moved(titleTextMove))
+	// Buttons.
+	closeButtonMove := pixel.V(mw.Size().X/2-mtk.ConvSize(20),
+		mw.Size().Y/2-mtk.ConvSize(15))
+	mw.closeButton.Draw(win, matrix.Moved(closeButtonMove))
+	// Map view.
+	viewMove := mtk.MoveBC(mw.Size(), mw.view.Size())
+	viewMove.Y += mtk.ConvSize(10)
+	mw.view.Draw(win, matrix.Moved(viewMove))
+}
+
+// Update updates window.
+func (mw *MapWindow) Update(win *mtk.Window) {
+	// Window fills whole game window with a small margin.
+	mw.size = win.Bounds().Size().Sub(mtk.ConvVec(pixel.V(40, 40)))
+	mw.view.SetSize(mw.viewSize())
+	// Key events.
+	if !mw.hud.Chat().Activated() && win.JustPressed(mapKey) {
+		if mw.Opened() {
+			mw.Hide()
+		} else {
+			mw.Show()
+		}
+	}
+	if win.JustPressed(exitKey) && mw.Opened() {
+		mw.Hide()
+	}
+	// Elements.
+	if !mw.Opened() {
+		return
+	}
+	mw.closeButton.Update(win)
+	if !mw.hud.Chat().Activated() {
+		mw.updatePan(win)
+		if win.JustPressed(mapZoomInKey) {
+			mw.zoom(mapZoomStep)
+		}
+		if win.JustPressed(mapZoomOutKey) {
+			mw.zoom(1 / mapZoomStep)
+		}
+	}
+	if !mw.view.DrawArea().Contains(win.MousePosition()) {
+		return
+	}
+	// Mouse events.
+	if scroll := win.MouseScroll().Y; scroll != 0 {
+		mw.zoom(math.Pow(mapZoomStep, scroll))
+	}
+	if win.Pressed(pixelgl.MouseButtonRight) {
+		drag := win.MousePosition().Sub(win.MousePreviousPosition())
+		mw.view.center = mw.view.center.Sub(drag.Scaled(1 / mw.view.scale))
+	}
+	if win.JustPressed(pixelgl.MouseButtonLeft) {
+		mw.view.moveTo(win.MousePosition())
+	}
+}
+
+// Show shows window.
+// Map view is scaled to show the whole area map, or
+// centered at the active player character if the map
+// is too big to fit in the view.
+func (mw *MapWindow) Show() {
+	mw.opened = true
+	mw.hud.Camera().Lock(true)
+	area := mw.hud.Camera().Area()
+	if area == nil || area.Map() == nil {
+		return
+	}
+	mapSize := area.Map().Size()
+	viewSize := mw.view.Size()
+	fitScale := math.Min(viewSize.X/mapSize.X, viewSize.Y/mapSize.Y)
+	mw.view.scale = 1
+	mw.zoom(fitScale)
+	mw.view.center = mapSize.Scaled(0.5)
+	if pc := mw.hud.PCAvatar(); pc != nil && mw.view.scale > fitScale {
+		mw.view.center = pc.Position()
+	}
+}
+
+// Hide hides window.
+func (mw *MapWindow) Hide() {
+	mw.opened = false
+	mw.hud.Camera().Lock(false)
+}
+
+// Opened checks if window is open.
+func (mw *MapWindow) Opened() bool {
+	return mw.opened
+}
+
+// DrawArea returns window draw area.
+func (mw *MapWindow) DrawArea() pixel.Rect {
+	return mw.drawArea
+}
+
+// Size returns window size.
+func (mw *MapWindow) Size() pixel.Vec {
+	return mw.size
+}
+
+// viewSize returns size of the map view for
+// the current window size.
+func (mw *MapWindow) viewSize() pixel.Vec {
+	return mw.size.Sub(mtk.ConvVec(pixel.V(20, 60)))
+}
+
+// updatePan moves the map view with the pan keys.
+func (mw *MapWindow) updatePan(win *mtk.Window) {
+	step := mtk.ConvSize(mapPanStep) / mw.view.scale
+	if win.Pressed(pixelgl.KeyW) || win.Pressed(pixelgl.KeyUp) {
+		mw.view.center.Y += step
+	}
+	if win.Pressed(pixelgl.KeyD) || win.Pressed(pixelgl.KeyRight) {
+		mw.view.center.X += step
+	}
+	if win.Pressed(pixelgl.KeyS) || win.Pressed(pixelgl.KeyDown) {
+		mw.view.center.Y -= step
+	}
+	if win.Pressed(pixelgl.KeyA) || win.Pressed(pixelgl.KeyLeft) {
+		mw.view.center.X -= step
+	}
+}
+
+// zoom multiplies the map view scale by specified factor,
+// within the scale limits.
+func (mw *MapWindow) zoom(factor float64) {
+	scale := mw.view.scale * factor
+	scale = math.Max(scale, mapMinScale)
+	scale = math.Min(scale, mapMaxScale)
+	mw.view.scale = scale
+}
+
+// Triggered after close button clicked.
+func (mw *MapWindow) onCloseButtonClicked(b *mtk.Button) {
+	mw.Hide()
+}
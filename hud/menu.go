@@ -154,6 +154,7 @@ func (m *Menu) Update(win *mtk.Window) {
 	}
 	// Elements.
 	if m.Opened() {
+		m.saveButton.Active(!m.hud.Game().Spectator())
 		m.closeButton.Update(win)
 		m.saveButton.Update(win)
 		m.exitButton.Update(win)
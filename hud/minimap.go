@@ -0,0 +1,83 @@
+/*
+ * minimap.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+
+	"github.com/isangeles/mtk"
+)
+
+const (
+	// Scale of the area map on the minimap.
+	minimapScale = 0.2
+)
+
+// Struct for HUD minimap.
+type Minimap struct {
+	hud  *HUD
+	view *mapView
+}
+
+// newMinimap creates new minimap for HUD.
+func newMinimap(hud *HUD) *Minimap {
+	m := new(Minimap)
+	m.hud = hud
+	m.view = newMapView(hud, mtk.ConvVec(pixel.V(200, 200)),
+		mtk.ConvSize(minimapScale))
+	return m
+}
+
+// Draw draws minimap centered at the active player
+// character.
+func (m *Minimap) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	pc := m.hud.PCAvatar()
+	if pc != nil {
+		m.view.center = pc.Position()
+	}
+	m.view.Draw(win, matrix)
+}
+
+// Update updates minimap.
+// Minimap click moves the player, unless the camera
+// is locked(by open menu or active chat).
+func (m *Minimap) Update(win *mtk.Window) {
+	if m.hud.Camera().Locked() {
+		return
+	}
+	if win.JustPressed(pixelgl.MouseButtonLeft) && m.DrawArea().Contains(win.MousePosition()) {
+		m.view.moveTo(win.MousePosition())
+	}
+}
+
+// DrawArea returns current minimap draw area.
+func (m *Minimap) DrawArea() pixel.Rect {
+	return m.view.DrawArea()
+}
+
+// Size returns minimap size.
+func (m *Minimap) Size() pixel.Vec {
+	return m.view.Size()
+}
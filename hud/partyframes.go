@@ -0,0 +1,287 @@
+/*
+ * partyframes.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/mural/data/res"
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/log"
+	"github.com/isangeles/mural/object"
+)
+
+var (
+	partyActiveColor   = pixel.RGBA{0.3, 0.3, 0.1, 0.3}
+	partySelectedColor = pixel.RGBA{0.1, 0.3, 0.1, 0.3}
+	partySelectKey     = pixelgl.KeyLeftControl
+	partyFollowKey     = pixelgl.KeyF
+	partyKeys          = []pixelgl.Button{pixelgl.KeyF1, pixelgl.KeyF2,
+		pixelgl.KeyF3, pixelgl.KeyF4}
+)
+
+// Struct for HUD party frames, with frame
+// for each player character.
+type PartyFrames struct {
+	hud      *HUD
+	frames   []*ObjectFrame
+	stances  []*mtk.Text
+	avatars  []*object.Avatar
+	chars    []*game.Character
+	selected map[string]*game.Character
+	drawArea pixel.Rect
+}
+
+// newPartyFrames creates new party frames.
+func newPartyFrames(hud *HUD) *PartyFrames {
+	pf := PartyFrames{
+		hud:      hud,
+		selected: make(map[string]*game.Character),
+	}
+	return &pf
+}
+
+// Draw draws party frames.
+func (pf *PartyFrames) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	pf.drawArea = pixel.R(0, 0, 0, 0)
+	if len(pf.chars) < 2 {
+		return
+	}
+	active := pf.hud.Game().ActivePlayerChar()
+	for i, f := range pf.frames {
+		frameMatrix := matrix.Moved(pixel.V(0, -f.Size().Y*float64(i)))
+		f.Draw(win, frameMatrix)
+		pf.drawArea = pf.drawArea.Union(f.DrawArea())
+		if pf.hud.Game().Companion(pf.chars[i]) {
+			stancePos := mtk.RightOf(f.DrawArea(), pf.stances[i].Size(), 5)
+			pf.stances[i].Draw(win, mtk.Matrix().Moved(stancePos))
+		}
+		switch {
+		case pf.chars[i] == active:
+			mtk.DrawRect(win, f.DrawArea(), partyActiveColor)
+		case pf.selected[pf.chars[i].ID()+pf.chars[i].Serial()] != nil:
+			mtk.DrawRect(win, f.DrawArea(), partySelectedColor)
+		}
+	}
+}
+
+// Update updates party frames.
+func (pf *PartyFrames) Update(win *mtk.Window) {
+	pf.updateFrames()
+	for _, f := range pf.frames {
+		f.Update(win)
+	}
+	// Mouse events.
+	if win.JustPressed(pixelgl.MouseButtonLeft) {
+		for i, f := range pf.frames {
+			if !f.DrawArea().Contains(win.MousePosition()) {
+				continue
+			}
+			pf.selectChar(pf.chars[i], win.Pressed(partySelectKey))
+			break
+		}
+	}
+	if win.JustPressed(pixelgl.MouseButtonRight) {
+		for i, f := range pf.frames {
+			if f.DrawArea().Contains(win.MousePosition()) {
+				pf.changeStance(pf.chars[i])
+				break
+			}
+		}
+	}
+	// Key events.
+	if pf.hud.Chat().Activated() {
+		return
+	}
+	for i, k := range partyKeys {
+		if i < len(pf.chars) && win.JustPressed(k) {
+			pf.selectChar(pf.chars[i], win.Pressed(partySelectKey))
+		}
+	}
+	if win.JustPressed(partyFollowKey) {
+		pf.hud.Game().SetFollowLeader(!pf.hud.Game().FollowLeader())
+	}
+}
+
+// DrawArea returns current draw area of all frames.
+func (pf *PartyFrames) DrawArea() pixel.Rect {
+	return pf.drawArea
+}
+
+// Selected returns all selected party members, with the active
+// player character as the first one.
+func (pf *PartyFrames) Selected() []*game.Character {
+	active := pf.hud.Game().ActivePlayerChar()
+	selected := []*game.Character{active}
+	for _, c := range pf.hud.Game().PlayerChars() {
+		if c != active && pf.selected[c.ID()+c.Serial()] != nil {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// Data returns party data.
+func (pf *PartyFrames) Data() res.Party {
+	data := res.Party{Follow: pf.hud.Game().FollowLeader()}
+	active := pf.hud.Game().ActivePlayerChar()
+	if active != nil {
+		data.Active = res.PartyMember{active.ID(), active.Serial()}
+	}
+	for _, c := range pf.Selected()[1:] {
+		data.Selected = append(data.Selected, res.PartyMember{c.ID(), c.Serial()})
+	}
+	for _, c := range pf.hud.Game().PlayerChars() {
+		if !pf.hud.Game().Companion(c) {
+			continue
+		}
+		stance := string(pf.hud.Game().CompanionStance(c))
+		data.Companions = append(data.Companions, res.Companion{c.ID(), c.Serial(), stance})
+	}
+	return data
+}
+
+// Apply applies specified data on party frames.
+func (pf *PartyFrames) Apply(data res.Party) {
+	pf.hud.Game().SetFollowLeader(data.Follow)
+	for _, cd := range data.Companions {
+		c := pf.hud.Game().Char(cd.ID, cd.Serial)
+		if c == nil {
+			log.Err.Printf("HUD: party: companion not found: %s %s", cd.ID, cd.Serial)
+			continue
+		}
+		err := pf.hud.Game().AddCompanion(c, game.Stance(cd.Stance))
+		if err != nil {
+			log.Err.Printf("HUD: party: unable to add companion: %v", err)
+		}
+	}
+	active := pf.hud.Game().Char(data.Active.ID, data.Active.Serial)
+	if active != nil && pf.member(active) {
+		pf.hud.Game().SetActivePlayerChar(active)
+	}
+	pf.selected = make(map[string]*game.Character)
+	for _, m := range data.Selected {
+		c := pf.hud.Game().Char(m.ID, m.Serial)
+		if c != nil && pf.member(c) {
+			pf.selected[c.ID()+c.Serial()] = c
+		}
+	}
+}
+
+// selectChar selects specified party member.
+// If add is true the character is added to or removed from
+// the current selection, otherwise the character becomes
+// the active player character.
+func (pf *PartyFrames) selectChar(char *game.Character, add bool) {
+	key := char.ID() + char.Serial()
+	if !add {
+		pf.selected = make(map[string]*game.Character)
+		pf.hud.Game().SetActivePlayerChar(char)
+		return
+	}
+	if char == pf.hud.Game().ActivePlayerChar() {
+		return
+	}
+	if pf.selected[key] != nil {
+		delete(pf.selected, key)
+		return
+	}
+	pf.selected[key] = char
+}
+
+// changeStance sets the next stance for specified character,
+// if the character is a companion.
+func (pf *PartyFrames) changeStance(char *game.Character) {
+	if !pf.hud.Game().Companion(char) {
+		return
+	}
+	stances := game.Stances()
+	next := stances[0]
+	for i, s := range stances {
+		if s == pf.hud.Game().CompanionStance(char) {
+			next = stances[(i+1)%len(stances)]
+			break
+		}
+	}
+	err := pf.hud.Game().SetCompanionStance(char, next)
+	if err != nil {
+		log.Err.Printf("HUD: party: unable to change companion stance: %v", err)
+	}
+}
+
+// updateFrames updates frames for player characters
+// from the current HUD area.
+func (pf *PartyFrames) updateFrames() {
+	chars := pf.hud.Game().PlayerChars()
+	if len(chars) != len(pf.chars) {
+		pf.frames = make([]*ObjectFrame, len(chars))
+		pf.avatars = make([]*object.Avatar, len(chars))
+		pf.stances = make([]*mtk.Text, len(chars))
+		stanceParams := mtk.Params{
+			FontSize: mtk.SizeSmall,
+		}
+		for i := range pf.frames {
+			pf.frames[i] = newObjectFrame(pf.hud)
+			pf.stances[i] = mtk.NewText(stanceParams)
+		}
+	}
+	pf.chars = chars
+	for i, c := range pf.chars {
+		if pf.hud.Game().Companion(c) {
+			stance := pf.hud.Game().CompanionStance(c)
+			pf.stances[i].SetText(lang.Text("hud_party_stance_" + string(stance)))
+		}
+	}
+	if pf.hud.camera.area == nil {
+		return
+	}
+	for i, c := range pf.chars {
+		for _, av := range pf.hud.camera.area.Avatars() {
+			if av.ID() != c.ID() || av.Serial() != c.Serial() {
+				continue
+			}
+			if pf.avatars[i] != av {
+				pf.avatars[i] = av
+				pf.frames[i].SetObject(av)
+			}
+			break
+		}
+	}
+}
+
+// member checks if specified character is a member of
+// the player party.
+func (pf *PartyFrames) member(char *game.Character) bool {
+	for _, c := range pf.hud.Game().PlayerChars() {
+		if c == char {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+/*
+ * spectator.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/object"
+)
+
+var (
+	spectatorNextCharKey = pixelgl.KeyTab
+	spectatorNextAreaKey = pixelgl.KeyPageDown
+	spectatorPrevAreaKey = pixelgl.KeyPageUp
+)
+
+// Struct for HUD spectator panel, with camera
+// controls for spectator mode.
+type Spectator struct {
+	hud    *HUD
+	info   *mtk.Text
+	follow *game.Character
+}
+
+// newSpectator creates new spectator panel.
+func newSpectator(hud *HUD) *Spectator {
+	s := Spectator{hud: hud}
+	infoParams := mtk.Params{
+		FontSize: mtk.SizeMedium,
+	}
+	s.info = mtk.NewText(infoParams)
+	return &s
+}
+
+// Draw draws spectator panel.
+func (s *Spectator) Draw(win *mtk.Window, matrix pixel.Matrix) {
+	s.info.Draw(win, matrix)
+}
+
+// Update updates spectator panel and camera position.
+func (s *Spectator) Update(win *mtk.Window) {
+	if s.hud.loading {
+		return
+	}
+	if s.hud.camera.Area() == nil {
+		startArea := s.hud.Game().Chapter().Area(s.hud.Game().Chapter().Conf().StartArea)
+		if startArea != nil {
+			go s.hud.ChangeArea(startArea)
+		}
+		return
+	}
+	// Key events.
+	if !s.hud.Chat().Activated() {
+		switch {
+		case win.JustPressed(spectatorNextCharKey):
+			s.followNextChar()
+		case win.JustPressed(spectatorNextAreaKey):
+			s.changeArea(1)
+		case win.JustPressed(spectatorPrevAreaKey):
+			s.changeArea(-1)
+		}
+	}
+	// Mouse events.
+	if !s.hud.containsPos(win.MousePosition()) {
+		if win.JustPressed(pixelgl.MouseButtonLeft) {
+			for _, av := range s.hud.camera.Area().Avatars() {
+				if av.DrawArea().Contains(win.MousePosition()) {
+					s.SetFollow(s.hud.Game().Char(av.ID(), av.Serial()))
+					break
+				}
+			}
+		}
+		if win.JustPressed(pixelgl.MouseButtonRight) {
+			s.SetFollow(nil)
+		}
+	}
+	s.updateFollow()
+	s.updateInfo()
+}
+
+// SetFollow sets character for camera to follow.
+// Nil value disables following.
+func (s *Spectator) SetFollow(char *game.Character) {
+	s.follow = char
+}
+
+// Follow returns character followed by the camera.
+func (s *Spectator) Follow() *game.Character {
+	return s.follow
+}
+
+// updateFollow centers camera at the followed character
+// and changes the camera area if the character moved to
+// another area.
+func (s *Spectator) updateFollow() {
+	if s.follow == nil {
+		return
+	}
+	charArea := s.hud.Game().Chapter().ObjectArea(s.follow)
+	if charArea == nil {
+		s.follow = nil
+		return
+	}
+	if charArea.ID() != s.hud.camera.Area().ID() {
+		go s.hud.ChangeArea(charArea)
+		return
+	}
+	av := s.avatar(s.follow)
+	if av != nil {
+		s.hud.camera.CenterAt(av.Position())
+	}
+}
+
+// updateInfo updates spectator info text.
+func (s *Spectator) updateInfo() {
+	if s.follow != nil {
+		s.info.SetText(fmt.Sprintf("%s: %s", lang.Text("hud_spectator_follow_label"),
+			s.follow.Name()))
+		return
+	}
+	s.info.SetText(fmt.Sprintf("%s: %s", lang.Text("hud_spectator_area_label"),
+		lang.Text(s.hud.camera.Area().ID())))
+}
+
+// followNextChar starts following the next character from
+// the current camera area.
+func (s *Spectator) followNextChar() {
+	avatars := s.hud.camera.Area().Avatars()
+	sort.Slice(avatars, func(i, j int) bool {
+		return avatars[i].ID()+avatars[i].Serial() < avatars[j].ID()+avatars[j].Serial()
+	})
+	next := 0
+	for i, av := range avatars {
+		if s.follow != nil && av.ID() == s.follow.ID() && av.Serial() == s.follow.Serial() {
+			next = i + 1
+			break
+		}
+	}
+	for i := 0; i < len(avatars); i++ {
+		av := avatars[(next+i)%len(avatars)]
+		char := s.hud.Game().Char(av.ID(), av.Serial())
+		if char != nil && av.Live() {
+			s.SetFollow(char)
+			return
+		}
+	}
+}
+
+// changeArea stops following and changes the camera area to
+// the area with specified offset from the current area on the
+// list of all areas with characters.
+func (s *Spectator) changeArea(offset int) {
+	areas := s.areas()
+	if len(areas) < 1 {
+		return
+	}
+	current := 0
+	for i, a := range areas {
+		if a.ID() == s.hud.camera.Area().ID() {
+			current = i
+			break
+		}
+	}
+	next := (current + offset + len(areas)) % len(areas)
+	s.SetFollow(nil)
+	go s.hud.ChangeArea(areas[next])
+}
+
+// areas returns all areas of the current chapter with at
+// least one character, sorted by ID.
+func (s *Spectator) areas() []*area.Area {
+	chapter := s.hud.Game().Chapter()
+	found := make(map[string]*area.Area)
+	for _, c := range chapter.Characters() {
+		a := chapter.ObjectArea(c)
+		if a != nil {
+			found[a.ID()] = a
+		}
+	}
+	areas := make([]*area.Area, 0, len(found))
+	for _, a := range found {
+		areas = append(areas, a)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		return areas[i].ID() < areas[j].ID()
+	})
+	return areas
+}
+
+// avatar returns avatar for specified character from the
+// current camera area.
+func (s *Spectator) avatar(char *game.Character) *object.Avatar {
+	for _, av := range s.hud.camera.Area().Avatars() {
+		if av.ID() == char.ID() && av.Serial() == char.Serial() {
+			return av
+		}
+	}
+	return nil
+}
@@ -1,7 +1,7 @@
 /*
  * tradewindow.go
  *
- * Copyright 2019-2024 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -38,27 +38,31 @@ import (
 
 	"github.com/isangeles/mural/data/res"
 	"github.com/isangeles/mural/data/res/graphic"
+	"github.com/isangeles/mural/game"
 	"github.com/isangeles/mural/object"
 	"github.com/isangeles/mural/log"
 )
 
 // Struct for HUD trade window.
 type TradeWindow struct {
-	hud         *HUD
-	bgSpr       *pixel.Sprite
-	bgDraw      *imdraw.IMDraw
-	drawArea    pixel.Rect
-	titleText   *mtk.Text
-	valueText   *mtk.Text
-	closeButton *mtk.Button
-	tradeButton *mtk.Button
-	buySlots    *mtk.SlotList
-	sellSlots   *mtk.SlotList
-	seller      item.Container
-	sellItems   map[string]item.Item
-	buyItems    map[string]item.Item
-	opened      bool
-	focused     bool
+	hud           *HUD
+	bgSpr         *pixel.Sprite
+	bgDraw        *imdraw.IMDraw
+	drawArea      pixel.Rect
+	titleText     *mtk.Text
+	valueText     *mtk.Text
+	closeButton   *mtk.Button
+	tradeButton   *mtk.Button
+	buybackButton *mtk.Button
+	buySlots      *mtk.SlotList
+	sellSlots     *mtk.SlotList
+	seller        item.Container
+	pricing       *game.Pricing
+	sellItems     map[string]item.Item
+	buyItems      map[string]item.Item
+	opened        bool
+	focused       bool
+	buyback       bool
 }
 
 var (
@@ -129,6 +133,15 @@ func newTradeWindow(hud *HUD) *TradeWindow {
 	}
 	tw.tradeButton.SetOnClickFunc(tw.onTradeButtonClicked)
 	tw.tradeButton.SetLabel(lang.Text("hud_trade_accept"))
+	// Buyback button.
+	tw.buybackButton = mtk.NewButton(tradeButtonParams)
+	if tradeButtonBG != nil {
+		bg := pixel.NewSprite(tradeButtonBG, tradeButtonBG.Bounds())
+		tw.buybackButton.SetBackground(bg)
+	}
+	tw.buybackButton.SetOnClickFunc(tw.onBuybackButtonClicked)
+	tw.buybackButton.SetLabel(lang.Text("hud_trade_buyback"))
+	tw.buybackButton.SetInfo(lang.Text("hud_trade_buyback_info"))
 	// Buy slot list.
 	tw.buySlots = mtk.NewSlotList(mtk.ConvVec(pixel.V(250, 150)),
 		tradeSlotColor, tradeSlotSize)
@@ -160,7 +173,6 @@ func newTradeWindow(hud *HUD) *TradeWindow {
 	} else {
 		log.Err.Printf("hud trade: unable to retrieve slot list down button texture")
 	}
-	tw.updateTradeValue()
 	return tw
 }
 
@@ -183,8 +195,11 @@ func (tw *TradeWindow) Draw(win *mtk.Window, matrix pixel.Matrix) {
 	closeButtonMove := pixel.V(tw.Size().X/2-mtk.ConvSize(20),
 		tw.Size().Y/2-mtk.ConvSize(15))
 	tradeButtonMove := pixel.V(mtk.ConvSize(50), -tw.Size().Y/2+mtk.ConvSize(30))
+	buybackButtonMove := pixel.V(-tw.Size().X/2+mtk.ConvSize(45),
+		tw.Size().Y/2-mtk.ConvSize(25))
 	tw.closeButton.Draw(win, matrix.Moved(closeButtonMove))
 	tw.tradeButton.Draw(win, matrix.Moved(tradeButtonMove))
+	tw.buybackButton.Draw(win, matrix.Moved(buybackButtonMove))
 	// Slot lists.
 	buySlotsMove := mtk.MoveTC(tw.Size(), tw.buySlots.Size())
 	buySlotsMove.Y -= mtk.ConvSize(50)
@@ -204,6 +219,9 @@ func (tw *TradeWindow) Update(win *mtk.Window) {
 	if tw.Opened() {
 		tw.closeButton.Update(win)
 		tw.tradeButton.Update(win)
+		tw.buybackButton.Update(win)
+		tw.updateSlotsColor(tw.buySlots, tw.buyItems)
+		tw.updateSlotsColor(tw.sellSlots, tw.sellItems)
 		tw.buySlots.Update(win)
 		tw.sellSlots.Update(win)
 	}
@@ -211,11 +229,15 @@ func (tw *TradeWindow) Update(win *mtk.Window) {
 
 // Show shows window.
 func (tw *TradeWindow) Show() {
-	tw.opened = true
-	if tw.seller != nil {
-		tw.insertBuyItems(tw.seller.Inventory().Items()...)
+	if tw.seller == nil {
+		return
 	}
-	tw.insertSellItems(tw.hud.Game().ActivePlayerChar().Inventory().Items()...)
+	tw.opened = true
+	pc := tw.hud.Game().ActivePlayerChar()
+	tw.pricing = tw.hud.Game().Pricing(tw.seller, pc)
+	tw.insertBuyItems(tw.merchantItems()...)
+	tw.insertSellItems(pc.Inventory().Items()...)
+	tw.updateTradeValue()
 }
 
 // Hide hides window.
@@ -263,13 +285,55 @@ func (tw *TradeWindow) reset() {
 	}
 }
 
+// updateSlotsColor sets proper colors for specified slots
+// with selected and pending items.
+func (tw *TradeWindow) updateSlotsColor(slots *mtk.SlotList, selected map[string]item.Item) {
+	for _, s := range slots.Slots() {
+		if tw.hud.slotPending(s) {
+			s.SetColor(pendingSlotColor)
+			continue
+		}
+		s.SetColor(tradeSlotColor)
+		for _, v := range s.Values() {
+			itg, ok := v.(*object.ItemGraphic)
+			if ok && selected[itg.ID()+itg.Serial()] != nil {
+				s.SetColor(tradeSelectSlotColor)
+				break
+			}
+		}
+	}
+}
+
 // tradeValue returns current trade value.
-func (tw *TradeWindow) tradeValue() (v int) {
-	for _, it := range tw.buyItems {
-		v -= itemTradeValue(tw.seller.Inventory(), it)
+func (tw *TradeWindow) tradeValue() int {
+	sellItems, buyItems := tw.tradeItems()
+	return tw.pricing.Value(sellItems, buyItems)
+}
+
+// merchantItems returns merchant items for the buy slots,
+// items from the merchant buyback list or the merchant stock
+// without the buyback items.
+func (tw *TradeWindow) merchantItems() []*item.InventoryItem {
+	buyback := make(map[string]bool)
+	for _, b := range tw.hud.Game().Buyback(tw.seller) {
+		buyback[b.Item.ID()+b.Item.Serial()] = true
+	}
+	items := make([]*item.InventoryItem, 0)
+	for _, it := range tw.seller.Inventory().Items() {
+		if buyback[it.ID()+it.Serial()] == tw.buyback {
+			items = append(items, it)
+		}
 	}
+	return items
+}
+
+// tradeItems returns all selected items to sell and buy.
+func (tw *TradeWindow) tradeItems() (sellItems, buyItems []item.Item) {
 	for _, it := range tw.sellItems {
-		v += it.Value()
+		sellItems = append(sellItems, it)
+	}
+	for _, it := range tw.buyItems {
+		buyItems = append(buyItems, it)
 	}
 	return
 }
@@ -316,6 +380,8 @@ func (tw *TradeWindow) insertBuyItems(items ...*item.InventoryItem) {
 		}
 		// Insert item to slot.
 		tw.hud.insertSlotItem(ig, slot)
+		slot.SetInfo(fmt.Sprintf("%s\n%s: %d", tw.hud.itemInfo(it.Item),
+			lang.Text("hud_trade_buy_price"), tw.pricing.BuyPrice(it.Item)))
 	}
 }
 
@@ -353,6 +419,12 @@ func (tw *TradeWindow) insertSellItems(items ...*item.InventoryItem) {
 		}
 		// Insert item to slot.
 		tw.hud.insertSlotItem(ig, slot)
+		priceInfo := lang.Text("hud_trade_refused")
+		if tw.pricing.Accepts(it.Item) {
+			priceInfo = fmt.Sprintf("%s: %d", lang.Text("hud_trade_sell_price"),
+				tw.pricing.SellPrice(it.Item))
+		}
+		slot.SetInfo(fmt.Sprintf("%s\n%s", tw.hud.itemInfo(it.Item), priceInfo))
 	}
 }
 
@@ -402,19 +474,26 @@ func (tw *TradeWindow) onTradeButtonClicked(b *mtk.Button) {
 		return
 	}
 	// Trade.
-	sellItems := make([]item.Item, 0)
-	for _, it := range tw.sellItems {
-		sellItems = append(sellItems, it)
-	}
-	buyItems := make([]item.Item, 0)
-	for _, it := range tw.buyItems {
-		buyItems = append(buyItems, it)
-	}
+	sellItems, buyItems := tw.tradeItems()
 	tw.hud.Game().Trade(tw.seller, tw.hud.Game().ActivePlayerChar(), sellItems, buyItems)
 	tw.Hide()
 	tw.Show()
 }
 
+// Triggered after buyback button clicked.
+// Toggles between the merchant stock and buyback list in
+// the buy slots.
+// Selected items stay selected after the toggle.
+func (tw *TradeWindow) onBuybackButtonClicked(b *mtk.Button) {
+	tw.buyback = !tw.buyback
+	label := lang.Text("hud_trade_buyback")
+	if tw.buyback {
+		label = lang.Text("hud_trade_stock")
+	}
+	tw.buybackButton.SetLabel(label)
+	tw.insertBuyItems(tw.merchantItems()...)
+}
+
 // Triggered after one of buy slots was clicked
 // with right mouse button.
 func (tw *TradeWindow) onBuySlotRightClicked(s *mtk.Slot) {
@@ -508,6 +587,10 @@ func (tw *TradeWindow) onSellSlotLeftClicked(s *mtk.Slot) {
 			log.Err.Printf("hud trade: invalid slot value: %v", v)
 			return
 		}
+		if !tw.pricing.Accepts(itg.Item) {
+			tw.refused()
+			return
+		}
 		tw.sellItems[itg.ID()+itg.Serial()] = itg.Item
 	}
 	s.SetColor(tradeSelectSlotColor)
@@ -526,6 +609,10 @@ func (tw *TradeWindow) onSellSlotSpecialLeftClicked(s *mtk.Slot) {
 			log.Err.Printf("hud trade: invalid slot value: %v", v)
 			return
 		}
+		if !tw.pricing.Accepts(itg.Item) {
+			tw.refused()
+			return
+		}
 		if tw.sellItems[itg.ID()+itg.Serial()] == nil {
 			tw.sellItems[itg.ID()+itg.Serial()] = itg.Item
 			break
@@ -541,12 +628,9 @@ func (tw *TradeWindow) onSellSlotSpecialRightClicked(s *mtk.Slot) {
 
 }
 
-// itemTradeValue returns item trading value inside specified
-// inventory.
-func itemTradeValue(inv *item.Inventory, it item.Item) int {
-	invIt := inv.Item(it.ID(), it.Serial())
-	if invIt == nil {
-		return it.Value()
-	}
-	return invIt.Price
+// refused adds message about item refused by the merchant
+// to the active player character log.
+func (tw *TradeWindow) refused() {
+	msg := objects.Message{Text: "hud_trade_refused_msg"}
+	tw.hud.Game().ActivePlayerChar().PrivateLog().Add(msg)
 }
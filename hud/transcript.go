@@ -0,0 +1,170 @@
+/*
+ * transcript.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/game"
+)
+
+const (
+	// Directory for chat transcripts in the GUI path.
+	TranscriptsDir = "transcripts"
+	// Formats of chat transcripts.
+	TranscriptText = "txt"
+	TranscriptJSON = "json"
+	// Layout of time in transcripts file names and text
+	// entries.
+	transcriptFileTime = "20060102-150405"
+	transcriptTime     = "2006-01-02 15:04:05"
+)
+
+// Replacer for escaping line breaks in text transcript
+// entries.
+var transcriptEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`,
+	"\r", `\r`)
+
+// Struct for chat transcript entry.
+type TranscriptEntry struct {
+	Time    time.Time `json:"time"`
+	Channel string    `json:"channel"`
+	Author  string    `json:"author"`
+	Text    string    `json:"text"`
+}
+
+// Struct for chat transcript file.
+// Text transcripts contain one entry per line, with line
+// breaks and backslashes in the message text escaped, JSON
+// transcripts one JSON object per line.
+type transcript struct {
+	file     *os.File
+	format   string
+	channels map[game.ChatChannel]bool
+}
+
+// newTranscript creates new timestamped transcript file in the
+// transcripts directory for messages from specified channels.
+// Messages from all channels are written if no channels are
+// specified.
+func newTranscript(format string, channels ...game.ChatChannel) (*transcript, error) {
+	if format != TranscriptText && format != TranscriptJSON {
+		return nil, fmt.Errorf("Unsupported format: %s", format)
+	}
+	t := transcript{
+		format:   format,
+		channels: make(map[game.ChatChannel]bool),
+	}
+	if len(channels) < 1 {
+		channels = game.ChatChannels()
+	}
+	for _, ch := range channels {
+		if !validChatChannel(ch) {
+			return nil, fmt.Errorf("Invalid channel: %s", ch)
+		}
+		t.channels[ch] = true
+	}
+	dir := filepath.Join(config.GUIPath, TranscriptsDir)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create transcripts directory: %v", err)
+	}
+	// Avoid overwriting transcript created in the same second.
+	stamp := time.Now().Format(transcriptFileTime)
+	path := filepath.Join(dir, fmt.Sprintf("chat-%s.%s", stamp, format))
+	for i := 1; ; i++ {
+		t.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("chat-%s-%d.%s", stamp, i, format))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create transcript file: %v", err)
+	}
+	return &t, nil
+}
+
+// Write writes specified message to the transcript, if the
+// message channel was selected for the transcript.
+func (t *transcript) Write(m Message) error {
+	if !t.channels[m.channel] {
+		return nil
+	}
+	entry := m.TranscriptEntry()
+	var line string
+	switch t.format {
+	case TranscriptJSON:
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("Unable to marshal entry: %v", err)
+		}
+		line = string(data)
+	default:
+		line = fmt.Sprintf("%s [%s] %s: %s", entry.Time.Format(transcriptTime),
+			entry.Channel, entry.Author, transcriptEscaper.Replace(entry.Text))
+	}
+	_, err := fmt.Fprintln(t.file, line)
+	if err != nil {
+		return fmt.Errorf("Unable to write entry: %v", err)
+	}
+	return nil
+}
+
+// Path returns path to the transcript file.
+func (t *transcript) Path() string {
+	return t.file.Name()
+}
+
+// Close closes the transcript file.
+func (t *transcript) Close() error {
+	return t.file.Close()
+}
+
+// Remove closes and removes the transcript file.
+func (t *transcript) Remove() error {
+	t.file.Close()
+	return os.Remove(t.file.Name())
+}
+
+// TranscriptEntry returns transcript entry for the message.
+// Entry contains translated name of the message author.
+func (m Message) TranscriptEntry() TranscriptEntry {
+	entry := TranscriptEntry{
+		Time:    m.time,
+		Channel: string(m.channel),
+		Author:  lang.Text(m.author),
+		Text:    strings.TrimSuffix(m.text, "\n"),
+	}
+	return entry
+}
@@ -0,0 +1,102 @@
+/*
+ * transcript_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package hud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/hud/internal"
+)
+
+// TestChatExport tests exporting chat messages to text and
+// JSON transcripts.
+func TestChatExport(t *testing.T) {
+	config.GUIPath = t.TempDir()
+	msgTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	chat := &Chat{buffer: internal.NewRing[Message](10)}
+	chat.buffer.Add(Message{"player", game.ChatSay, msgTime, "hello\n"})
+	chat.buffer.Add(Message{"enemy", game.ChatCombat, msgTime, "hit\n"})
+	chat.buffer.Add(Message{"player", game.ChatSay, msgTime, "multi\nline\\n\n"})
+	// Text.
+	path, err := chat.Export(TranscriptText, game.ChatSay)
+	if err != nil {
+		t.Fatalf("Unable to export text transcript: %v", err)
+	}
+	lines := readTranscript(t, path)
+	expLines := []string{
+		fmt.Sprintf("2026-01-02 03:04:05 [say] %s: hello", lang.Text("player")),
+		fmt.Sprintf(`2026-01-02 03:04:05 [say] %s: multi\nline\\n`, lang.Text("player")),
+	}
+	if len(lines) != len(expLines) {
+		t.Fatalf("Invalid number of text transcript lines: %d != %d",
+			len(lines), len(expLines))
+	}
+	for i, l := range expLines {
+		if lines[i] != l {
+			t.Errorf("Invalid text transcript line %d: '%s' != '%s'", i, lines[i], l)
+		}
+	}
+	// JSON.
+	path, err = chat.Export(TranscriptJSON)
+	if err != nil {
+		t.Fatalf("Unable to export JSON transcript: %v", err)
+	}
+	lines = readTranscript(t, path)
+	if len(lines) != 3 {
+		t.Fatalf("Invalid number of JSON transcript entries: %d != 3", len(lines))
+	}
+	var entry TranscriptEntry
+	err = json.Unmarshal([]byte(lines[1]), &entry)
+	if err != nil {
+		t.Fatalf("Unable to unmarshal JSON transcript entry: %v", err)
+	}
+	if !entry.Time.Equal(msgTime) || entry.Channel != "combat" ||
+		entry.Author != lang.Text("enemy") || entry.Text != "hit" {
+		t.Errorf("Invalid JSON transcript entry: %v", entry)
+	}
+	// Invalid format.
+	_, err = chat.Export("invalid")
+	if err == nil {
+		t.Errorf("No error for invalid transcript format")
+	}
+}
+
+// readTranscript returns lines of the transcript file under
+// specified path.
+func readTranscript(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read transcript: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
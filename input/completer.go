@@ -0,0 +1,176 @@
+/*
+ * completer.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package input
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/isangeles/burn"
+)
+
+const (
+	// Prefixes of command flags.
+	optionFlag = "-o"
+	targetFlag = "-t"
+	// Prefix of pipe operators.
+	pipePrefix = "|"
+)
+
+var (
+	toolsMutex sync.RWMutex
+	tools      = make(map[string][]string)
+)
+
+// On init.
+func init() {
+	AddTool(burn.EngineShow)
+	AddTool(burn.EngineExport)
+	AddTool(burn.ResShow)
+	AddTool(burn.ModuleAdd)
+	AddTool(burn.ModuleRemove)
+	AddTool(burn.ModuleShow)
+	AddTool(burn.ChapterShow)
+	AddTool(burn.AreaSet)
+	AddTool(burn.AreaShow)
+	AddTool(burn.ObjectAdd)
+	AddTool(burn.ObjectRemove)
+	AddTool(burn.ObjectSet)
+	AddTool(burn.ObjectShow)
+	AddTool(burn.ObjectHave)
+	AddTool(burn.ObjectUse)
+}
+
+// AddTool adds CI tool with specified name and options
+// to the completion.
+func AddTool(name string, options ...string) {
+	toolsMutex.Lock()
+	defer toolsMutex.Unlock()
+	tools[name] = options
+}
+
+// Struct for completer of the CI commands.
+// Completes tool names, tool options and targets.
+type Completer struct {
+	targets func() []string
+}
+
+// NewCompleter creates new commands completer.
+func NewCompleter() *Completer {
+	c := new(Completer)
+	return c
+}
+
+// SetTargetsFunc sets function that returns targets
+// for completion, in the [id]#[serial] format.
+func (c *Completer) SetTargetsFunc(f func() []string) {
+	c.targets = f
+}
+
+// Complete completes the last word of specified command.
+// Returns completed command and all completion candidates.
+// If there are many candidates the word is completed to the
+// longest prefix common for all candidates.
+func (c *Completer) Complete(command string) (string, []string) {
+	candidates := c.Candidates(command)
+	if len(candidates) < 1 {
+		return command, candidates
+	}
+	start := strings.LastIndex(command, " ") + 1
+	if len(candidates) == 1 {
+		return command[:start] + candidates[0] + " ", candidates
+	}
+	prefix := candidates[0]
+	for _, cand := range candidates[1:] {
+		for !strings.HasPrefix(cand, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return command[:start] + prefix, candidates
+}
+
+// Candidates returns sorted completion candidates for the last
+// word of specified command.
+// The first word of the command(or the first word after pipe)
+// is completed with tool names, word after the option flag with
+// tool options and words after the target flag with targets.
+func (c *Completer) Candidates(command string) []string {
+	fields := strings.Split(command, " ")
+	word := fields[len(fields)-1]
+	args := fields[:len(fields)-1]
+	for i := len(args) - 1; i >= 0; i-- {
+		if strings.HasPrefix(args[i], pipePrefix) {
+			args = args[i+1:]
+			break
+		}
+	}
+	var words []string
+	switch {
+	case len(args) < 1:
+		words = toolNames()
+	case lastFlag(args) == optionFlag && args[len(args)-1] == optionFlag:
+		words = toolOptions(args[0])
+	case lastFlag(args) == targetFlag && c.targets != nil:
+		words = c.targets()
+	}
+	candidates := make([]string, 0)
+	for _, w := range words {
+		if strings.HasPrefix(w, word) {
+			candidates = append(candidates, w)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// toolNames returns names of all tools.
+func toolNames() []string {
+	toolsMutex.RLock()
+	defer toolsMutex.RUnlock()
+	names := make([]string, 0, len(tools))
+	for n := range tools {
+		names = append(names, n)
+	}
+	return names
+}
+
+// toolOptions returns options of the tool with
+// specified name.
+func toolOptions(name string) []string {
+	toolsMutex.RLock()
+	defer toolsMutex.RUnlock()
+	return tools[name]
+}
+
+// lastFlag returns the last flag from specified command
+// arguments, or empty string if there is no flag.
+func lastFlag(args []string) string {
+	for i := len(args) - 1; i >= 0; i-- {
+		if strings.HasPrefix(args[i], "-") {
+			return args[i]
+		}
+	}
+	return ""
+}
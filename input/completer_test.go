@@ -0,0 +1,60 @@
+/*
+ * completer_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package input
+
+import (
+	"testing"
+)
+
+// TestCompleterComplete tests completing commands.
+func TestCompleterComplete(t *testing.T) {
+	AddTool("testset", "resolution", "res-path", "fow")
+	c := NewCompleter()
+	c.SetTargetsFunc(func() []string {
+		return []string{"char#0", "char#1", "npc#0"}
+	})
+	tests := []struct {
+		command string
+		result  string
+	}{
+		{"testse", "testset "},
+		{"testset -o f", "testset -o fow "},
+		{"testset -o re", "testset -o res"},
+		{"testset -o fow -a o", "testset -o fow -a o"},
+		{"objectshow -o pos -t n", "objectshow -o pos -t npc#0 "},
+		{"objectshow -o pos -t npc#0 ch", "objectshow -o pos -t npc#0 char#"},
+		{"objectshow -o pos -t npc#0 |t testset -o f", "objectshow -o pos -t npc#0 |t testset -o fow "},
+	}
+	for _, test := range tests {
+		result, _ := c.Complete(test.command)
+		if result != test.result {
+			t.Errorf("Invalid completion of '%s': '%s' != '%s'", test.command,
+				result, test.result)
+		}
+	}
+	_, candidates := c.Complete("testset -o re")
+	if len(candidates) != 2 || candidates[0] != "res-path" {
+		t.Errorf("Invalid candidates: %v", candidates)
+	}
+}
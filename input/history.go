@@ -0,0 +1,137 @@
+/*
+ * history.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package with utilities for text input.
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Struct for input history.
+type History struct {
+	entries []string
+	size    int
+	pos     int
+}
+
+// NewHistory creates new input history with specified
+// maximal number of entries.
+func NewHistory(size int) *History {
+	h := History{size: size}
+	return &h
+}
+
+// Add adds specified input as the newest history entry
+// and resets the browsing position.
+// Empty input and input same as the newest entry are
+// ignored.
+func (h *History) Add(input string) {
+	defer h.Reset()
+	if len(strings.TrimSpace(input)) < 1 {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == input {
+		return
+	}
+	h.entries = append(h.entries, input)
+	if h.size > 0 && len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+}
+
+// Prev moves the browsing position to the previous entry
+// and returns it.
+// Returns the oldest entry if there is no previous entry,
+// or empty string if the history is empty.
+func (h *History) Prev() string {
+	if len(h.entries) < 1 {
+		return ""
+	}
+	if h.pos > 0 {
+		h.pos--
+	}
+	return h.entries[h.pos]
+}
+
+// Next moves the browsing position to the next entry
+// and returns it.
+// Returns empty string after passing the newest entry.
+func (h *History) Next() string {
+	if h.pos < len(h.entries) {
+		h.pos++
+	}
+	if h.pos == len(h.entries) {
+		return ""
+	}
+	return h.entries[h.pos]
+}
+
+// Reset moves the browsing position after the newest
+// entry.
+func (h *History) Reset() {
+	h.pos = len(h.entries)
+}
+
+// Entries returns all history entries, from the oldest
+// to the newest.
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Load adds entries from the file with specified path
+// to the history.
+// Missing file is not considered as an error.
+func (h *History) Load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Unable to open history file: %v", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Unable to read history file: %v", err)
+	}
+	return nil
+}
+
+// Save saves all history entries in the file with
+// specified path, one entry per line.
+func (h *History) Save(path string) error {
+	text := strings.Join(h.entries, "\n")
+	err := os.WriteFile(path, []byte(text), 0600)
+	if err != nil {
+		return fmt.Errorf("Unable to write history file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+ * history_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package input
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestHistory tests browsing the input history.
+func TestHistory(t *testing.T) {
+	h := NewHistory(2)
+	if h.Prev() != "" {
+		t.Errorf("Invalid entry of empty history: '%s'", h.Prev())
+	}
+	h.Add("first")
+	h.Add("second")
+	h.Add("second")
+	h.Add("third")
+	if len(h.Entries()) != 2 {
+		t.Fatalf("Invalid number of entries: %d != 2", len(h.Entries()))
+	}
+	if e := h.Prev(); e != "third" {
+		t.Errorf("Invalid previous entry: '%s' != 'third'", e)
+	}
+	if e := h.Prev(); e != "second" {
+		t.Errorf("Invalid previous entry: '%s' != 'second'", e)
+	}
+	if e := h.Prev(); e != "second" {
+		t.Errorf("Invalid oldest entry: '%s' != 'second'", e)
+	}
+	if e := h.Next(); e != "third" {
+		t.Errorf("Invalid next entry: '%s' != 'third'", e)
+	}
+	if e := h.Next(); e != "" {
+		t.Errorf("Invalid entry after the newest entry: '%s'", e)
+	}
+}
+
+// TestHistorySave tests saving and loading the input
+// history.
+func TestHistorySave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h := NewHistory(10)
+	err := h.Load(path)
+	if err != nil {
+		t.Fatalf("Unable to load missing history file: %v", err)
+	}
+	h.Add("$guishow -o version")
+	h.Add("hello")
+	err = h.Save(path)
+	if err != nil {
+		t.Fatalf("Unable to save history: %v", err)
+	}
+	loaded := NewHistory(10)
+	err = loaded.Load(path)
+	if err != nil {
+		t.Fatalf("Unable to load history: %v", err)
+	}
+	if len(loaded.Entries()) != 2 || loaded.Prev() != "hello" {
+		t.Errorf("Invalid loaded entries: %v", loaded.Entries())
+	}
+}
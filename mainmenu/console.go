@@ -39,6 +39,8 @@ import (
 
 	"github.com/isangeles/mtk"
 
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/input"
 	"github.com/isangeles/mural/log"
 )
 
@@ -52,7 +54,8 @@ type Console struct {
 	msgs      map[string]*flamelog.Message
 	drawArea  pixel.Rect
 	opened    bool
-	lastInput string
+	history   *input.History
+	completer *input.Completer
 	onCommand func(cmd string) (int, string, error)
 }
 
@@ -70,6 +73,9 @@ func newConsole(mainmenu *MainMenu) *Console {
 	c.textbox = mtk.NewTextbox(textboxParams)
 	// Text input.
 	c.textedit = mtk.NewTextedit(textboxParams)
+	// Input history and completion.
+	c.history = input.NewHistory(config.HistorySize)
+	c.completer = input.NewCompleter()
 	return c
 }
 
@@ -96,8 +102,14 @@ func (c *Console) Update(win *mtk.Window) {
 		}
 		defer c.textedit.Clear()
 	}
-	if win.JustPressed(pixelgl.KeyUp) {
-		c.textedit.SetText(c.lastInput)
+	if c.opened && win.JustPressed(pixelgl.KeyUp) {
+		c.textedit.SetText(c.history.Prev())
+	}
+	if c.opened && win.JustPressed(pixelgl.KeyDown) {
+		c.textedit.SetText(c.history.Next())
+	}
+	if c.opened && win.JustPressed(pixelgl.KeyTab) {
+		c.complete()
 	}
 	if win.JustPressed(pixelgl.KeyEnter) {
 		c.onEnterPressed()
@@ -132,6 +144,12 @@ func (c *Console) Opened() bool {
 	return c.opened
 }
 
+// SetHistory sets specified history as console
+// input history.
+func (c *Console) SetHistory(h *input.History) {
+	c.history = h
+}
+
 // Echo prints specified text to console.
 func (c *Console) Echo(text string) {
 	log.Cli.Printf("%s", text)
@@ -145,7 +163,7 @@ func (c *Console) onEnterPressed() {
 		return
 	}
 	c.Echo(input)
-	c.lastInput = input
+	c.history.Add(input)
 	defer c.textedit.Clear()
 	// Execute command.
 	if !strings.HasPrefix(input, guiCommandPrefix) && c.mainmenu.server != nil {
@@ -165,6 +183,16 @@ func (c *Console) onEnterPressed() {
 	log.Cli.Printf("[%d]: %s", res, out)
 }
 
+// complete completes the console input and prints
+// completion candidates if there is more than one.
+func (c *Console) complete() {
+	text, candidates := c.completer.Complete(c.textedit.Text())
+	c.textedit.SetText(text)
+	if len(candidates) > 1 {
+		c.Echo(strings.Join(candidates, " "))
+	}
+}
+
 // executeCommand handles specified text line
 // as CI command.
 // Returns result code and output text, or error if
@@ -205,7 +205,11 @@ func (lgm *LoadGameMenu) loadSavedGame(saveName string) error {
 			continue
 		}
 		pc := game.NewCharacter(char, gameWrapper)
-		gameWrapper.AddPlayerChar(pc)
+		err := gameWrapper.AddPlayerChar(pc)
+		if err != nil {
+			log.Err.Printf("Main menu: load game: unable to add pc character: %v",
+				err)
+		}
 	}
 	// Enter game.
 	if lgm.mainmenu.onGameCreated != nil {
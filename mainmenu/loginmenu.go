@@ -31,6 +31,8 @@ import (
 	"github.com/isangeles/fire/request"
 
 	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/mural/config"
 )
 
 // Struct for login menu.
@@ -44,6 +46,8 @@ type LoginMenu struct {
 	loginButton *mtk.Button
 	backButton  *mtk.Button
 	opened      bool
+	login       string
+	pass        string
 }
 
 // newLoginMenu creates new login menu.
@@ -113,6 +117,13 @@ func (lm *LoginMenu) Draw(win *mtk.Window) {
 // Update updates all menu elements.
 func (lm *LoginMenu) Update(win *mtk.Window) {
 	if lm.mainmenu.server != nil && lm.mainmenu.server.Authorized() {
+		// Remember user credential for auto-login, Fire
+		// protocol has no session token yet.
+		if len(lm.login) > 0 {
+			config.ServerLogin = lm.login
+			config.ServerPassword = lm.pass
+			lm.login, lm.pass = "", ""
+		}
 		lm.mainmenu.ShowMessage(lang.Text("login_logged_in_msg"))
 		lm.mainmenu.OpenMenu()
 		return
@@ -126,6 +137,9 @@ func (lm *LoginMenu) Update(win *mtk.Window) {
 // Show shows menu.
 func (lm *LoginMenu) Show() {
 	lm.opened = true
+	if len(lm.loginEdit.Text()) < 1 {
+		lm.loginEdit.SetText(config.ServerLogin)
+	}
 }
 
 // Hide hides menu.
@@ -145,7 +159,9 @@ func (lm *LoginMenu) onLoginButtonClicked(b *mtk.Button) {
 	err := lm.mainmenu.server.Send(req)
 	if err != nil {
 		lm.mainmenu.ShowMessage(lang.Text("login_menu_create_login_req_err"))
+		return
 	}
+	lm.login, lm.pass = lm.loginEdit.Text(), lm.passEdit.Text()
 }
 
 // Triggered on back button click.
@@ -69,11 +69,13 @@ type MainMenu struct {
 	newgamemenu   *NewGameMenu
 	newcharmenu   *NewCharacterMenu
 	loadgamemenu  *LoadGameMenu
+	serversmenu   *ServersMenu
 	settings      *Settings
 	console       *Console
 	loadscreen    *LoadingScreen
 	userFocus     *mtk.Focus
 	msgs          *mtk.MessageQueue
+	connInfo      *mtk.Text
 	music         *mtk.AudioPlayer
 	server        *game.Server
 	mod           *flame.Module
@@ -107,6 +109,7 @@ func New(modData flameres.ModuleData) *MainMenu {
 	mm.newgamemenu = newNewGameMenu(mm)
 	mm.newcharmenu = newNewCharacterMenu(mm)
 	mm.loadgamemenu = newLoadGameMenu(mm)
+	mm.serversmenu = newServersMenu(mm)
 	mm.settings = newSettings(mm)
 	// Console.
 	mm.console = newConsole(mm)
@@ -115,6 +118,11 @@ func New(modData flameres.ModuleData) *MainMenu {
 	// Messages & focus.
 	mm.userFocus = new(mtk.Focus)
 	mm.msgs = mtk.NewMessageQueue(mm.userFocus)
+	// Server connection info.
+	connInfoParams := mtk.Params{
+		FontSize: mtk.SizeMedium,
+	}
+	mm.connInfo = mtk.NewText(connInfoParams)
 	// Music.
 	mm.music = mtk.NewAudioPlayer()
 	mm.music.SetVolume(config.MusicVolume)
@@ -145,9 +153,17 @@ func (mm *MainMenu) Draw(win *mtk.Window) {
 	if mm.loadgamemenu.Opened() {
 		mm.loadgamemenu.Draw(win)
 	}
+	if mm.serversmenu.Opened() {
+		mm.serversmenu.Draw(win)
+	}
 	if mm.settings.Opened() {
 		mm.settings.Draw(win.Window)
 	}
+	// Server connection info.
+	if mm.server != nil && mm.server.State() != game.Connected {
+		connInfoPos := mtk.DrawPosBC(win.Bounds(), mm.connInfo.Size())
+		mm.connInfo.Draw(win, mtk.Matrix().Moved(connInfoPos))
+	}
 	// Messages.
 	mm.msgs.Draw(win.Window, mtk.Matrix().Moved(win.Bounds().Center()))
 	// Console.
@@ -188,9 +204,15 @@ func (mm *MainMenu) Update(win *mtk.Window) {
 	if mm.loadgamemenu.Opened() {
 		mm.loadgamemenu.Update(win)
 	}
+	if mm.serversmenu.Opened() {
+		mm.serversmenu.Update(win)
+	}
 	if mm.settings.Opened() {
 		mm.settings.Update(win)
 	}
+	if mm.server != nil {
+		mm.connInfo.SetText(mm.server.State().Info())
+	}
 	mm.console.Update(win)
 	mm.msgs.Update(win)
 }
@@ -223,6 +245,36 @@ func (mm *MainMenu) SetServer(server *game.Server) {
 	}
 }
 
+// Connect closes connection with the current game server
+// and connects to the server with specified host and port.
+func (mm *MainMenu) Connect(host, port string, tls bool) error {
+	var recorder *game.Recorder
+	if len(config.ServerRecord) > 0 {
+		r, err := game.NewSessionRecorder(config.ServerRecord)
+		if err != nil {
+			log.Err.Printf("Main menu: unable to record server session: %v", err)
+		} else {
+			recorder = r
+		}
+	}
+	server, err := game.NewRecordedServer(host, port, tls, recorder)
+	if err != nil {
+		if recorder != nil {
+			recorder.Close()
+		}
+		return fmt.Errorf("Unable to create server: %v", err)
+	}
+	if mm.server != nil {
+		err := mm.server.Close()
+		if err != nil {
+			log.Err.Printf("Main menu: unable to close server connection: %v", err)
+		}
+	}
+	mm.continueChars = nil
+	mm.SetServer(server)
+	return nil
+}
+
 // Exit sends exit request to main menu.
 func (mm *MainMenu) Exit() {
 	mm.exiting = true
@@ -244,6 +296,30 @@ func (mm *MainMenu) OpenMenu() {
 	}
 }
 
+// OpenLoginMenu opens login menu.
+func (mm *MainMenu) OpenLoginMenu() {
+	mm.HideMenus()
+	mm.loginmenu.Show()
+}
+
+// Logout ends the current user session on the game server,
+// removes stored user credential and opens login menu.
+func (mm *MainMenu) Logout() {
+	if mm.server == nil {
+		return
+	}
+	err := mm.server.Logout()
+	if err != nil {
+		log.Err.Printf("Main menu: unable to logout: %v", err)
+		mm.ShowMessage(lang.Text("logout_err"))
+		return
+	}
+	config.ServerPassword = ""
+	mm.loginmenu.passEdit.SetText("")
+	mm.continueChars = nil
+	mm.OpenLoginMenu()
+}
+
 // OpenNewGameMenu opens new game creation menu.
 func (mm *MainMenu) OpenNewGameMenu() {
 	mm.HideMenus()
@@ -262,6 +338,12 @@ func (mm *MainMenu) OpenLoadGameMenu() {
 	mm.loadgamemenu.Show()
 }
 
+// OpenServersMenu opens game servers menu.
+func (mm *MainMenu) OpenServersMenu() {
+	mm.HideMenus()
+	mm.serversmenu.Show()
+}
+
 // OpenSettings opens settings menu.
 func (mm *MainMenu) OpenSettings() {
 	mm.HideMenus()
@@ -287,6 +369,7 @@ func (mm *MainMenu) HideMenus() {
 	mm.newgamemenu.Hide()
 	mm.newcharmenu.Hide()
 	mm.loadgamemenu.Hide()
+	mm.serversmenu.Hide()
 	mm.settings.Hide()
 }
 
@@ -398,8 +481,28 @@ func (mm *MainMenu) continueGame() {
 	// Create players.
 	for _, c := range mm.continueChars {
 		pc := game.NewCharacter(c, gameWrapper)
-		gameWrapper.AddPlayerChar(pc)
+		err := gameWrapper.AddPlayerChar(pc)
+		if err != nil {
+			log.Err.Printf("Main menu: continue: unable to add player character: %v",
+				err)
+		}
+	}
+	// Trigger game created function.
+	if mm.onGameCreated != nil {
+		mm.onGameCreated(gameWrapper, nil)
 	}
+}
+
+// spectateGame creates game without player characters in
+// spectator mode and triggers onGameCreated function.
+func (mm *MainMenu) spectateGame() {
+	// Show loading screen.
+	mm.OpenLoadingScreen(lang.Text("loading_game_info"))
+	defer mm.CloseLoadingScreen()
+	// Create game.
+	gameWrapper := game.New(mm.mod)
+	gameWrapper.SetServer(mm.server)
+	gameWrapper.SetSpectator(true)
 	// Trigger game created function.
 	if mm.onGameCreated != nil {
 		mm.onGameCreated(gameWrapper, nil)
@@ -37,10 +37,13 @@ type Menu struct {
 	mainmenu       *MainMenu
 	title          *mtk.Text
 	loginButton    *mtk.Button
+	logoutButton   *mtk.Button
 	continueButton *mtk.Button
+	spectateButton *mtk.Button
 	newgameB       *mtk.Button
 	newcharB       *mtk.Button
 	loadgameB      *mtk.Button
+	serversB       *mtk.Button
 	settingsB      *mtk.Button
 	exitB          *mtk.Button
 	opened         bool
@@ -68,10 +71,18 @@ func newMenu(mainmenu *MainMenu) *Menu {
 	m.loginButton.SetLabel(lang.Text("login_button_label"))
 	m.loginButton.SetInfo(lang.Text("login_button_info"))
 	m.loginButton.SetOnClickFunc(m.onLoginButtonClicked)
+	m.logoutButton = mtk.NewButton(buttonParams)
+	m.logoutButton.SetLabel(lang.Text("logout_button_label"))
+	m.logoutButton.SetInfo(lang.Text("logout_button_info"))
+	m.logoutButton.SetOnClickFunc(m.onLogoutButtonClicked)
 	m.continueButton = mtk.NewButton(buttonParams)
 	m.continueButton.SetLabel(lang.Text("continue_button_label"))
 	m.continueButton.SetInfo(lang.Text("continue_button_info"))
 	m.continueButton.SetOnClickFunc(m.onContinueButtonClicked)
+	m.spectateButton = mtk.NewButton(buttonParams)
+	m.spectateButton.SetLabel(lang.Text("spectate_button_label"))
+	m.spectateButton.SetInfo(lang.Text("spectate_button_info"))
+	m.spectateButton.SetOnClickFunc(m.onSpectateButtonClicked)
 	m.newgameB = mtk.NewButton(buttonParams)
 	m.newgameB.SetLabel(lang.Text("newgame_button_label"))
 	m.newgameB.SetInfo(lang.Text("newgame_button_info"))
@@ -84,6 +95,10 @@ func newMenu(mainmenu *MainMenu) *Menu {
 	m.loadgameB.SetLabel(lang.Text("loadgame_button_label"))
 	m.loadgameB.SetInfo(lang.Text("loadgame_button_info"))
 	m.loadgameB.SetOnClickFunc(m.onLoadGameButtonClicked)
+	m.serversB = mtk.NewButton(buttonParams)
+	m.serversB.SetLabel(lang.Text("servers_button_label"))
+	m.serversB.SetInfo(lang.Text("servers_button_info"))
+	m.serversB.SetOnClickFunc(m.onServersButtonClicked)
 	m.settingsB = mtk.NewButton(buttonParams)
 	m.settingsB.SetLabel(lang.Text("settings_button_label"))
 	m.settingsB.SetInfo(lang.Text("settings_button_info"))
@@ -104,16 +119,24 @@ func (m *Menu) Draw(win *mtk.Window) {
 	m.title.Draw(win.Window, mtk.Matrix().Moved(titlePos))
 	// Buttons.
 	loginPos := mtk.BottomOf(m.title.DrawArea(), m.loginButton.Size(), 10)
-	m.loginButton.Draw(win.Window, mtk.Matrix().Moved(loginPos))
-	continuePos := mtk.BottomOf(m.loginButton.DrawArea(), m.newgameB.Size(), 5)
+	loginButton := m.loginButton
+	if m.mainmenu.server != nil && m.mainmenu.server.Authorized() {
+		loginButton = m.logoutButton
+	}
+	loginButton.Draw(win.Window, mtk.Matrix().Moved(loginPos))
+	continuePos := mtk.BottomOf(loginButton.DrawArea(), m.newgameB.Size(), 5)
 	m.continueButton.Draw(win.Window, mtk.Matrix().Moved(continuePos))
-	newgamePos := mtk.BottomOf(m.continueButton.DrawArea(), m.newgameB.Size(), 5)
+	spectatePos := mtk.BottomOf(m.continueButton.DrawArea(), m.spectateButton.Size(), 5)
+	m.spectateButton.Draw(win.Window, mtk.Matrix().Moved(spectatePos))
+	newgamePos := mtk.BottomOf(m.spectateButton.DrawArea(), m.newgameB.Size(), 5)
 	m.newgameB.Draw(win.Window, mtk.Matrix().Moved(newgamePos))
 	newcharPos := mtk.BottomOf(m.newgameB.DrawArea(), m.newcharB.Size(), 5)
 	m.newcharB.Draw(win.Window, mtk.Matrix().Moved(newcharPos))
 	loadgamePos := mtk.BottomOf(m.newcharB.DrawArea(), m.loadgameB.Size(), 5)
 	m.loadgameB.Draw(win.Window, mtk.Matrix().Moved(loadgamePos))
-	settingsPos := mtk.BottomOf(m.loadgameB.DrawArea(), m.settingsB.Size(), 5)
+	serversPos := mtk.BottomOf(m.loadgameB.DrawArea(), m.serversB.Size(), 5)
+	m.serversB.Draw(win.Window, mtk.Matrix().Moved(serversPos))
+	settingsPos := mtk.BottomOf(m.serversB.DrawArea(), m.settingsB.Size(), 5)
 	m.settingsB.Draw(win.Window, mtk.Matrix().Moved(settingsPos))
 	exitPos := mtk.BottomOf(m.settingsB.DrawArea(), m.exitB.Size(), 5)
 	m.exitB.Draw(win.Window, mtk.Matrix().Moved(exitPos))
@@ -122,22 +145,29 @@ func (m *Menu) Draw(win *mtk.Window) {
 // Update updates all menu elements.
 func (m *Menu) Update(win *mtk.Window) {
 	m.continueButton.Active(len(m.mainmenu.continueChars) > 0)
+	m.spectateButton.Active(m.mainmenu.server != nil && m.mainmenu.server.Authorized() &&
+		m.mainmenu.mod != nil)
 	if m.mainmenu.server == nil || m.mainmenu.server.Authorized() {
 		m.loginButton.Active(false)
+		m.logoutButton.Active(m.mainmenu.server != nil)
 		m.newgameB.Active(len(m.mainmenu.PlayableChars()) > 0)
 		m.newcharB.Active(m.mainmenu.mod != nil)
 		m.loadgameB.Active(true)
 	} else {
 		m.loginButton.Active(true)
+		m.logoutButton.Active(false)
 		m.newgameB.Active(false)
 		m.newcharB.Active(false)
 		m.loadgameB.Active(false)
 	}
 	m.loginButton.Update(win)
+	m.logoutButton.Update(win)
 	m.continueButton.Update(win)
+	m.spectateButton.Update(win)
 	m.newgameB.Update(win)
 	m.newcharB.Update(win)
 	m.loadgameB.Update(win)
+	m.serversB.Update(win)
 	m.settingsB.Update(win)
 	m.exitB.Update(win)
 }
@@ -159,8 +189,12 @@ func (m *Menu) Hide() {
 
 // Triggered after login button clicked.
 func (m *Menu) onLoginButtonClicked(b *mtk.Button) {
-	m.mainmenu.HideMenus()
-	m.mainmenu.loginmenu.Show()
+	m.mainmenu.OpenLoginMenu()
+}
+
+// Triggered after logout button clicked.
+func (m *Menu) onLogoutButtonClicked(b *mtk.Button) {
+	m.mainmenu.Logout()
 }
 
 // Triggered after continue button clicked.
@@ -168,6 +202,11 @@ func (m *Menu) onContinueButtonClicked(b *mtk.Button) {
 	go m.mainmenu.continueGame()
 }
 
+// Triggered after spectate button clicked.
+func (m *Menu) onSpectateButtonClicked(b *mtk.Button) {
+	go m.mainmenu.spectateGame()
+}
+
 // Triggered after new game button clicked.
 func (m *Menu) onNewGameButtonClicked(b *mtk.Button) {
 	m.mainmenu.OpenNewGameMenu()
@@ -184,6 +223,11 @@ func (m *Menu) onLoadGameButtonClicked(b *mtk.Button) {
 	m.mainmenu.OpenLoadGameMenu()
 }
 
+// Triggered after servers button clicked.
+func (m *Menu) onServersButtonClicked(b *mtk.Button) {
+	m.mainmenu.OpenServersMenu()
+}
+
 // onSettingsButtonClicked closes all currently open
 // menus and opens settings menu.
 func (m *Menu) onSettingsButtonClicked(b *mtk.Button) {
@@ -266,7 +266,11 @@ func (ngm *NewGameMenu) startGame() {
 				err)
 			return
 		}
-		gameWrapper.AddPlayerChar(pc)
+		err = gameWrapper.AddPlayerChar(pc)
+		if err != nil {
+			log.Err.Printf("main menu: new game: unable to add player: %v",
+				err)
+		}
 	}
 	// Default HUD setup.
 	var hudData res.HUDData
@@ -24,11 +24,13 @@
 package mainmenu
 
 import (
+	"fmt"
 	"path/filepath"
 	"sync"
 
 	"github.com/isangeles/flame"
 	flameres "github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/data/res/lang"
 	"github.com/isangeles/flame/serial"
 
 	"github.com/isangeles/fire/response"
@@ -48,7 +50,9 @@ func (mm *MainMenu) handleResponse(resp response.Response) {
 		if len(resp.Load.Save) > 0 {
 			mm.handleLoadResponse(resp.Load)
 		}
-		mm.handleUpdateResponse(resp.Update)
+		if len(resp.Update.Module.Config) > 0 {
+			mm.handleUpdateResponse(resp.Update)
+		}
 		for _, r := range resp.Character {
 			mm.handleCharacterResponse(r)
 		}
@@ -58,6 +62,7 @@ func (mm *MainMenu) handleResponse(resp response.Response) {
 	}
 	for _, r := range resp.Error {
 		log.Err.Printf("Main menu: server error: %v", r)
+		mm.ShowMessage(fmt.Sprintf("%s: %s", lang.Text("server_err"), r))
 	}
 }
 
@@ -0,0 +1,151 @@
+/*
+ * response_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package mainmenu
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	flameres "github.com/isangeles/flame/data/res"
+
+	"github.com/isangeles/fire/response"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/data"
+	"github.com/isangeles/mural/data/res"
+	"github.com/isangeles/mural/game"
+)
+
+// TestMainMenuUpdateResponse tests applying server updates
+// and adding continue characters from the server responses.
+func TestMainMenuUpdateResponse(t *testing.T) {
+	config.GUIPath = t.TempDir()
+	mm, loopback, handled := newLoopbackMainMenu(t)
+	defer mm.server.Close()
+	mm.mod = newTestModule()
+	// Create server module state.
+	serverMod := newTestModule()
+	char := character.New(flameres.CharacterData{ID: "char", Level: 1})
+	serverMod.Chapter().Area("area").AddObject(char)
+	update := serverMod.Data()
+	update.Config = map[string][]string{"id": {serverMod.Conf().ID}}
+	// Test.
+	err := loopback.Respond(response.Response{Update: response.Update{Module: update}})
+	if err != nil {
+		t.Fatalf("Unable to queue update response: %v", err)
+	}
+	waitResponse(t, handled)
+	charResp := response.Character{ID: char.ID(), Serial: char.Serial()}
+	err = loopback.Respond(response.Response{Character: []response.Character{charResp}})
+	if err != nil {
+		t.Fatalf("Unable to queue character response: %v", err)
+	}
+	waitResponse(t, handled)
+	if len(mm.continueChars) != 1 {
+		t.Fatalf("Invalid number of continue characters: %d != 1",
+			len(mm.continueChars))
+	}
+	if mm.continueChars[0].ID() != char.ID() || mm.continueChars[0].Serial() != char.Serial() {
+		t.Errorf("Invalid continue character: %s %s != %s %s", mm.continueChars[0].ID(),
+			mm.continueChars[0].Serial(), char.ID(), char.Serial())
+	}
+}
+
+// TestMainMenuLoadResponse tests creating game from the server
+// load response.
+func TestMainMenuLoadResponse(t *testing.T) {
+	config.GUIPath = t.TempDir()
+	hudPath := filepath.Join(config.GUIPath, data.HUDDir, "save"+data.HUDFileExt)
+	err := data.ExportHUD(res.HUDData{}, hudPath)
+	if err != nil {
+		t.Fatalf("Unable to export HUD: %v", err)
+	}
+	mm, loopback, handled := newLoopbackMainMenu(t)
+	defer mm.server.Close()
+	var created *game.Game
+	mm.SetOnGameCreatedFunc(func(g *game.Game, h *res.HUDData) {
+		created = g
+	})
+	// Test.
+	load := response.Load{Save: "save", Module: newTestModule().Data()}
+	err = loopback.Respond(response.Response{Load: load})
+	if err != nil {
+		t.Fatalf("Unable to queue load response: %v", err)
+	}
+	waitResponse(t, handled)
+	if created == nil {
+		t.Fatalf("Game not created")
+	}
+	if created.Server() != mm.server {
+		t.Errorf("Game not connected to the server")
+	}
+	if created.Chapter().Area("area") == nil {
+		t.Errorf("Game module not loaded")
+	}
+}
+
+// newLoopbackMainMenu creates new main menu connected to the
+// server via loopback transport.
+// Returned channel receives a value after each handled response.
+func newLoopbackMainMenu(t *testing.T) (*MainMenu, *game.Loopback, chan struct{}) {
+	config.ServerLogin = ""
+	mm := &MainMenu{playableChars: new(sync.Map)}
+	loopback := game.NewLoopback()
+	server, err := game.NewTransportServer(func() (game.Transport, error) {
+		return loopback, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to create server: %v", err)
+	}
+	mm.SetServer(server)
+	handled := make(chan struct{}, 1)
+	server.SetOnResponseFunc(func(r response.Response) {
+		mm.handleResponse(r)
+		handled <- struct{}{}
+	})
+	return mm, loopback, handled
+}
+
+// newTestModule creates new module with one area.
+func newTestModule() *flame.Module {
+	mod := flame.NewModule(flameres.ModuleData{})
+	mod.Chapter().AddAreas(area.New(flameres.AreaData{ID: "area"}))
+	mod.Chapter().Conf().StartArea = "area"
+	return mod
+}
+
+// waitResponse waits for handling of the server response,
+// test fails if the response is not handled after one second.
+func waitResponse(t *testing.T, handled chan struct{}) {
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatalf("Server response not handled")
+	}
+}
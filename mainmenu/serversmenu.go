@@ -0,0 +1,380 @@
+/*
+ * serversmenu.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package mainmenu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/mtk"
+
+	"github.com/isangeles/mural/config"
+	"github.com/isangeles/mural/game"
+	"github.com/isangeles/mural/log"
+)
+
+var (
+	// Timeout for probing game servers.
+	serverProbeTimeout = 3 * time.Second
+)
+
+// Struct for game servers menu.
+type ServersMenu struct {
+	mainmenu      *MainMenu
+	title         *mtk.Text
+	serversList   *mtk.List
+	nameLabel     *mtk.Text
+	hostLabel     *mtk.Text
+	portLabel     *mtk.Text
+	nameEdit      *mtk.Textedit
+	hostEdit      *mtk.Textedit
+	portEdit      *mtk.Textedit
+	tlsSwitch     *mtk.Switch
+	saveButton    *mtk.Button
+	removeButton  *mtk.Button
+	testButton    *mtk.Button
+	connectButton *mtk.Button
+	backButton    *mtk.Button
+	opened        bool
+	probesMutex   sync.Mutex
+	probes        map[string]string
+	probed        bool
+}
+
+// newServersMenu creates new servers menu.
+func newServersMenu(mainmenu *MainMenu) *ServersMenu {
+	sm := new(ServersMenu)
+	sm.mainmenu = mainmenu
+	sm.probes = make(map[string]string)
+	// Title.
+	titleParams := mtk.Params{
+		FontSize: mtk.SizeBig,
+	}
+	sm.title = mtk.NewText(titleParams)
+	sm.title.SetText(lang.Text("servers_menu_title"))
+	// Servers list.
+	listSize := mtk.SizeBig.ListSize()
+	listParams := mtk.Params{
+		SizeRaw:     listSize,
+		MainColor:   mainColor,
+		SecColor:    secColor,
+		AccentColor: accentColor,
+		FontSize:    mtk.SizeMedium,
+	}
+	sm.serversList = mtk.NewList(listParams)
+	sm.serversList.SetOnItemSelectFunc(sm.onServerSelected)
+	// Labels.
+	labelParams := mtk.Params{
+		FontSize: mtk.SizeMedium,
+	}
+	sm.nameLabel = mtk.NewText(labelParams)
+	sm.nameLabel.SetText(lang.Text("servers_menu_name_label"))
+	sm.hostLabel = mtk.NewText(labelParams)
+	sm.hostLabel.SetText(lang.Text("servers_menu_host_label"))
+	sm.portLabel = mtk.NewText(labelParams)
+	sm.portLabel.SetText(lang.Text("servers_menu_port_label"))
+	// Text edit fields.
+	texteditParams := mtk.Params{
+		FontSize:  mtk.SizeMedium,
+		MainColor: mainColor,
+	}
+	sm.nameEdit = mtk.NewTextedit(texteditParams)
+	sm.hostEdit = mtk.NewTextedit(texteditParams)
+	sm.portEdit = mtk.NewTextedit(texteditParams)
+	// TLS switch.
+	switchParams := mtk.Params{
+		Size:      mtk.SizeMedium,
+		MainColor: mainColor,
+	}
+	sm.tlsSwitch = mtk.NewSwitch(switchParams)
+	sm.tlsSwitch.SetLabel(lang.Text("servers_menu_tls_switch_label"))
+	tlsTrue := mtk.SwitchValue{lang.Text("com_yes"), true}
+	tlsFalse := mtk.SwitchValue{lang.Text("com_no"), false}
+	sm.tlsSwitch.SetValues(tlsFalse, tlsTrue)
+	// Buttons.
+	buttonParams := mtk.Params{
+		Size:      mtk.SizeMedium,
+		FontSize:  mtk.SizeMedium,
+		Shape:     mtk.ShapeRectangle,
+		MainColor: accentColor,
+	}
+	sm.saveButton = mtk.NewButton(buttonParams)
+	sm.saveButton.SetLabel(lang.Text("save_button_label"))
+	sm.saveButton.SetInfo(lang.Text("servers_menu_save_button_info"))
+	sm.saveButton.SetOnClickFunc(sm.onSaveButtonClicked)
+	sm.removeButton = mtk.NewButton(buttonParams)
+	sm.removeButton.SetLabel(lang.Text("servers_menu_remove_button_label"))
+	sm.removeButton.SetOnClickFunc(sm.onRemoveButtonClicked)
+	sm.testButton = mtk.NewButton(buttonParams)
+	sm.testButton.SetLabel(lang.Text("servers_menu_test_button_label"))
+	sm.testButton.SetInfo(lang.Text("servers_menu_test_button_info"))
+	sm.testButton.SetOnClickFunc(sm.onTestButtonClicked)
+	sm.connectButton = mtk.NewButton(buttonParams)
+	sm.connectButton.SetLabel(lang.Text("servers_menu_connect_button_label"))
+	sm.connectButton.SetOnClickFunc(sm.onConnectButtonClicked)
+	sm.backButton = mtk.NewButton(buttonParams)
+	sm.backButton.SetLabel(lang.Text("back_button_label"))
+	sm.backButton.SetOnClickFunc(sm.onBackButtonClicked)
+	return sm
+}
+
+// Draw draws all menu elements in specified window.
+func (sm *ServersMenu) Draw(win *mtk.Window) {
+	// Title.
+	titlePos := pixel.V(win.Bounds().Center().X,
+		win.Bounds().H()-sm.title.Size().Y)
+	sm.title.Draw(win.Window, mtk.Matrix().Moved(titlePos))
+	// Servers list.
+	serversListPos := win.Bounds().Center()
+	serversListPos.X -= sm.serversList.Size().X / 2
+	sm.serversList.Draw(win.Window, mtk.Matrix().Moved(serversListPos))
+	// Profile fields.
+	editSize := pixel.V(sm.serversList.Size().X/2, sm.nameLabel.Size().Y*1.5)
+	nameLabelPos := mtk.RightOf(sm.serversList.DrawArea(), editSize, 20)
+	nameLabelPos.Y = sm.serversList.DrawArea().Max.Y - sm.nameLabel.Size().Y
+	sm.nameLabel.Draw(win, mtk.Matrix().Moved(nameLabelPos))
+	sm.nameEdit.SetSize(editSize)
+	nameEditPos := mtk.BottomOf(sm.nameLabel.DrawArea(), editSize, 10)
+	sm.nameEdit.Draw(win.Window, mtk.Matrix().Moved(nameEditPos))
+	hostLabelPos := mtk.BottomOf(sm.nameEdit.DrawArea(), sm.hostLabel.Size(), 10)
+	sm.hostLabel.Draw(win, mtk.Matrix().Moved(hostLabelPos))
+	sm.hostEdit.SetSize(editSize)
+	hostEditPos := mtk.BottomOf(sm.hostLabel.DrawArea(), editSize, 10)
+	sm.hostEdit.Draw(win.Window, mtk.Matrix().Moved(hostEditPos))
+	portLabelPos := mtk.BottomOf(sm.hostEdit.DrawArea(), sm.portLabel.Size(), 10)
+	sm.portLabel.Draw(win, mtk.Matrix().Moved(portLabelPos))
+	sm.portEdit.SetSize(editSize)
+	portEditPos := mtk.BottomOf(sm.portLabel.DrawArea(), editSize, 10)
+	sm.portEdit.Draw(win.Window, mtk.Matrix().Moved(portEditPos))
+	tlsSwitchPos := mtk.BottomOf(sm.portEdit.DrawArea(), sm.tlsSwitch.Size(), 10)
+	sm.tlsSwitch.Draw(win, mtk.Matrix().Moved(tlsSwitchPos))
+	// Buttons.
+	saveButtonPos := mtk.BottomOf(sm.tlsSwitch.DrawArea(), sm.saveButton.Size(), 10)
+	sm.saveButton.Draw(win.Window, mtk.Matrix().Moved(saveButtonPos))
+	removeButtonPos := mtk.BottomOf(sm.saveButton.DrawArea(), sm.removeButton.Size(), 5)
+	sm.removeButton.Draw(win.Window, mtk.Matrix().Moved(removeButtonPos))
+	testButtonPos := mtk.BottomOf(sm.removeButton.DrawArea(), sm.testButton.Size(), 5)
+	sm.testButton.Draw(win.Window, mtk.Matrix().Moved(testButtonPos))
+	backButtonPos := mtk.DrawPosBL(win.Bounds(), sm.backButton.Size())
+	sm.backButton.Draw(win.Window, mtk.Matrix().Moved(backButtonPos))
+	connectButtonPos := mtk.DrawPosBR(win.Bounds(), sm.connectButton.Size())
+	sm.connectButton.Draw(win.Window, mtk.Matrix().Moved(connectButtonPos))
+}
+
+// Update updates all menu elements.
+func (sm *ServersMenu) Update(win *mtk.Window) {
+	sm.probesMutex.Lock()
+	probed := sm.probed
+	sm.probed = false
+	sm.probesMutex.Unlock()
+	if probed {
+		sm.updateServers()
+	}
+	sm.connectButton.Active(sm.serversList.SelectedValue() != nil)
+	sm.removeButton.Active(sm.serversList.SelectedValue() != nil)
+	sm.serversList.Update(win)
+	sm.nameEdit.Update(win)
+	sm.hostEdit.Update(win)
+	sm.portEdit.Update(win)
+	sm.tlsSwitch.Update(win)
+	sm.saveButton.Update(win)
+	sm.removeButton.Update(win)
+	sm.testButton.Update(win)
+	sm.connectButton.Update(win)
+	sm.backButton.Update(win)
+}
+
+// Show shows menu and starts probing all server
+// profiles.
+func (sm *ServersMenu) Show() {
+	sm.opened = true
+	sm.updateServers()
+	for _, p := range config.ServerProfiles {
+		go sm.probe(p)
+	}
+}
+
+// Hide hides menu.
+func (sm *ServersMenu) Hide() {
+	sm.opened = false
+}
+
+// Opened checks whether menu is open.
+func (sm *ServersMenu) Opened() bool {
+	return sm.opened
+}
+
+// updateServers updates servers list with current
+// server profiles and probe results.
+func (sm *ServersMenu) updateServers() {
+	sm.serversList.Clear()
+	sm.probesMutex.Lock()
+	defer sm.probesMutex.Unlock()
+	for _, p := range config.ServerProfiles {
+		label := fmt.Sprintf("%s (%s:%s)", p.Name, p.Host, p.Port)
+		if p.Name == config.ServerProfileName {
+			label = "* " + label
+		}
+		if status := sm.probes[p.Name]; len(status) > 0 {
+			label = fmt.Sprintf("%s: %s", label, status)
+		}
+		sm.serversList.AddItem(label, p.Name)
+	}
+}
+
+// probe checks if the server from specified profile is
+// reachable and saves probe result for the profile.
+func (sm *ServersMenu) probe(profile config.ServerProfile) string {
+	sm.probesMutex.Lock()
+	sm.probes[profile.Name] = lang.Text("servers_menu_probing_info")
+	sm.probed = true
+	sm.probesMutex.Unlock()
+	status := lang.Text("servers_menu_unreachable_info")
+	rtt, err := game.ProbeServer(profile.Host, profile.Port, profile.TLS,
+		serverProbeTimeout)
+	if err == nil {
+		status = fmt.Sprintf("%s %v", lang.Text("servers_menu_reachable_info"),
+			rtt.Round(time.Millisecond))
+	}
+	sm.probesMutex.Lock()
+	defer sm.probesMutex.Unlock()
+	sm.probes[profile.Name] = status
+	sm.probed = true
+	return status
+}
+
+// editedProfile returns server profile with values from
+// the profile edit fields.
+func (sm *ServersMenu) editedProfile() config.ServerProfile {
+	profile := config.ServerProfile{
+		Name: sm.nameEdit.Text(),
+		Host: sm.hostEdit.Text(),
+		Port: sm.portEdit.Text(),
+	}
+	tls, ok := sm.tlsSwitch.Value().Value.(bool)
+	if !ok {
+		log.Err.Printf("Servers menu: unable to retrieve tls switch value")
+	}
+	profile.TLS = tls
+	return profile
+}
+
+// selectedProfile returns profile selected on the servers
+// list, or nil if no profile is selected.
+func (sm *ServersMenu) selectedProfile() *config.ServerProfile {
+	name, ok := sm.serversList.SelectedValue().(string)
+	if !ok {
+		return nil
+	}
+	return config.Profile(name)
+}
+
+// Triggered after selecting one of servers list items.
+func (sm *ServersMenu) onServerSelected(cs *mtk.CheckSlot) {
+	name, ok := cs.Value().(string)
+	if !ok {
+		log.Err.Printf("Servers menu: unable to retrieve profile name")
+		return
+	}
+	profile := config.Profile(name)
+	if profile == nil {
+		return
+	}
+	sm.nameEdit.SetText(profile.Name)
+	sm.hostEdit.SetText(profile.Host)
+	sm.portEdit.SetText(profile.Port)
+	sm.tlsSwitch.SetIndex(sm.tlsSwitch.Find(profile.TLS))
+}
+
+// Triggered after save button clicked.
+func (sm *ServersMenu) onSaveButtonClicked(b *mtk.Button) {
+	profile := sm.editedProfile()
+	err := config.SetProfile(profile)
+	if err != nil {
+		log.Err.Printf("Servers menu: unable to save profile: %v", err)
+		sm.mainmenu.ShowMessage(lang.Text("servers_menu_invalid_profile_err"))
+		return
+	}
+	sm.updateServers()
+	go sm.probe(profile)
+}
+
+// Triggered after remove button clicked.
+func (sm *ServersMenu) onRemoveButtonClicked(b *mtk.Button) {
+	profile := sm.selectedProfile()
+	if profile == nil {
+		return
+	}
+	config.RemoveProfile(profile.Name)
+	sm.updateServers()
+}
+
+// Triggered after test button clicked.
+func (sm *ServersMenu) onTestButtonClicked(b *mtk.Button) {
+	profile := sm.editedProfile()
+	go func() {
+		status := sm.probe(profile)
+		sm.mainmenu.ShowMessage(fmt.Sprintf("%s:%s: %s", profile.Host,
+			profile.Port, status))
+	}()
+}
+
+// Triggered after connect button clicked.
+func (sm *ServersMenu) onConnectButtonClicked(b *mtk.Button) {
+	profile := sm.selectedProfile()
+	if profile == nil {
+		return
+	}
+	go sm.connect(*profile)
+}
+
+// connect connects main menu to the server from specified
+// profile, the profile is selected only after successful
+// connection.
+func (sm *ServersMenu) connect(profile config.ServerProfile) {
+	// Show loading screen.
+	sm.mainmenu.OpenLoadingScreen(lang.Text("servers_menu_connect_info"))
+	defer sm.mainmenu.CloseLoadingScreen()
+	err := sm.mainmenu.Connect(profile.Host, profile.Port, profile.TLS)
+	if err != nil {
+		log.Err.Printf("Servers menu: unable to connect: %v", err)
+		sm.mainmenu.ShowMessage(lang.Text("servers_menu_connect_err"))
+		return
+	}
+	err = config.SelectProfile(profile.Name)
+	if err != nil {
+		log.Err.Printf("Servers menu: unable to select profile: %v", err)
+	}
+	sm.mainmenu.OpenMenu()
+}
+
+// Triggered after back button clicked.
+func (sm *ServersMenu) onBackButtonClicked(b *mtk.Button) {
+	sm.mainmenu.OpenMenu()
+}
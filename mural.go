@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"golang.org/x/image/colornames"
 
@@ -55,6 +56,7 @@ import (
 	"github.com/isangeles/mural/data/res/graphic"
 	"github.com/isangeles/mural/game"
 	"github.com/isangeles/mural/hud"
+	"github.com/isangeles/mural/input"
 	"github.com/isangeles/mural/log"
 	"github.com/isangeles/mural/mainmenu"
 )
@@ -65,6 +67,8 @@ var (
 	gameHUD    *hud.HUD
 	activeGame *game.Game
 	inGame     bool
+	history    *input.History
+	areaReload atomic.Bool
 )
 
 // Main function.
@@ -73,8 +77,15 @@ func main() {
 	config.Load()
 	defer config.Save()
 	log.PrintStdOut(config.Debug)
+	// Load input history.
+	history = input.NewHistory(config.HistorySize)
+	err := history.Load(config.HistoryFileName)
+	if err != nil {
+		log.Err.Printf("Unable to load input history: %v", err)
+	}
+	defer saveHistory()
 	// Load GUI graphic data.
-	err := data.LoadModuleData(config.GUIPath)
+	err = data.LoadModuleData(config.GUIPath)
 	if err != nil {
 		panic(fmt.Errorf("Unable to load GUI data: %v", err))
 	}
@@ -144,6 +155,7 @@ func run() {
 	// Create main menu
 	mainMenu = mainmenu.New(modData)
 	mainMenu.SetOnGameCreatedFunc(enterGame)
+	mainMenu.Console().SetHistory(history)
 	ci.SetMainMenu(mainMenu)
 	go enterMainMenu()
 	// Main loop.
@@ -169,11 +181,23 @@ func run() {
 			mainMenu.Update(win)
 			continue
 		}
+		// Reload HUD area after chapter change made by the server.
+		if areaReload.Swap(false) {
+			err := gameHUD.ReloadArea()
+			if err != nil {
+				log.Err.Printf("Server chapter change: Unable to reload HUD area: %v", err)
+			}
+		}
 		gameHUD.Update(win)
 		if gameHUD.Exiting() || activeGame.Closing() {
 			go enterMainMenu()
 		}
 	}
+	// Stop collecting chat messages and close the session
+	// transcript.
+	if gameHUD != nil {
+		gameHUD.Chat().Close()
+	}
 }
 
 // enterMainMenu exits the game and prepares the main menu.
@@ -181,19 +205,43 @@ func enterMainMenu() {
 	mainMenu.OpenLoadingScreen(lang.Text("enter_menu_info"))
 	defer mainMenu.CloseLoadingScreen()
 	inGame = false
+	if activeGame != nil {
+		activeGame.Stop()
+	}
+	if gameHUD != nil {
+		gameHUD.Chat().Close()
+	}
 	burn.Module = mainMenu.Module()
 	serial.Reset() // reset serial values after previous game
+	// Replay recorded server session(if configured)
+	if mainMenu.Server() == nil && len(config.ServerReplay) > 0 {
+		server, err := replayServer(config.ServerReplay, config.ServerReplaySpeed)
+		if err != nil {
+			log.Err.Printf("Unable to replay server session: %v", err)
+			return
+		}
+		mainMenu.SetServer(server)
+		return
+	}
 	// Connect to the game server(if needed/configured)
 	if mainMenu.Server() == nil && len(config.ServerHost+config.ServerPort) > 1 {
-		server, err := game.NewServer(config.ServerHost, config.ServerPort, config.ServerTLS)
+		err := mainMenu.Connect(config.ServerHost, config.ServerPort, config.ServerTLS)
 		if err != nil {
 			log.Err.Printf("Unable to connect to the game server: %v",
 				err)
 		}
-		mainMenu.SetServer(server)
 	}
 }
 
+// replayServer creates game server that replays session
+// recorded in file under specified path.
+func replayServer(path string, speed float64) (*game.Server, error) {
+	dial := func() (game.Transport, error) {
+		return game.NewReplay(path, speed)
+	}
+	return game.NewTransportServer(dial)
+}
+
 // enterGame creates HUD and enters game.
 // The second parameter is used to apply data on newly
 // created HUD, if nil the HUD will be left in its
@@ -203,10 +251,12 @@ func enterGame(g *game.Game, hudData *res.HUDData) {
 	defer mainMenu.CloseLoadingScreen()
 	activeGame = g
 	activeGame.AddChangeChapterEvent(changeChapter)
+	activeGame.SetOnChapterChangeFunc(serverChangeChapter)
 	// Create HUD.
 	hud := hud.New(win)
 	// Set HUD.
 	gameHUD = hud
+	gameHUD.Chat().SetHistory(history)
 	ci.SetHUD(gameHUD)
 	// Load GUI data.
 	chapterGUIPath := filepath.Join(config.GUIPath, "chapters", activeGame.Chapter().Conf().ID)
@@ -267,6 +317,21 @@ func changeChapter(ob *character.Character) {
 	}
 }
 
+// serverChangeChapter handles chapter change made by the game server.
+// The chapter is already changed by the server update, so only GUI
+// data for the new chapter needs to be loaded.
+// HUD area is reloaded by the main loop, also if the GUI data failed
+// to load, since the old area is no longer a part of the game.
+func serverChangeChapter(chapter *flame.Chapter) {
+	// Load GUI data.
+	chapterGUIPath := filepath.Join(config.GUIPath, "chapters", chapter.Conf().ID)
+	err := data.LoadChapterData(chapterGUIPath)
+	if err != nil {
+		log.Err.Printf("Server chapter change: Unable to load chapter GUI data: %v", err)
+	}
+	areaReload.Store(true)
+}
+
 // runModuleScripts starts all scripts from the module
 // GUI directory(scripts/run).
 func runModuleScripts() error {
@@ -283,3 +348,11 @@ func runModuleScripts() error {
 	}
 	return nil
 }
+
+// saveHistory saves the input history in the history file.
+func saveHistory() {
+	err := history.Save(config.HistoryFileName)
+	if err != nil {
+		log.Err.Printf("Unable to save input history: %v", err)
+	}
+}
@@ -59,6 +59,7 @@ type Area struct {
 	*area.Area
 	game    *game.Game
 	areaMap *stone.Map
+	grid    *passGrid
 	fow     *imdraw.IMDraw
 	avatars *sync.Map
 }
@@ -72,6 +73,9 @@ func NewArea(game *game.Game, area *area.Area, areaMap *stone.Map) *Area {
 		fow:     imdraw.New(nil),
 		avatars: new(sync.Map),
 	}
+	if areaMap != nil {
+		a.grid = newPassGrid(areaMap)
+	}
 	a.updateObjects()
 	return a
 }
@@ -208,6 +208,11 @@ func (av *Avatar) Update(win *mtk.Window) {
 	av.sprite.Update(win)
 	// Chat.
 	for _, m := range av.ChatLog().Messages() {
+		// Party and whisper messages are not displayed above
+		// the avatar.
+		if game.ParseChat(m.String()).Channel != game.ChatSay {
+			continue
+		}
 		duration := time.Since(m.Time)
 		av.speaking = duration.Seconds() < 2
 		if av.speaking {
@@ -0,0 +1,286 @@
+/*
+ * path.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package object
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/stone"
+
+	"github.com/isangeles/mural/game"
+)
+
+const (
+	// Max number of nodes visited by the path search,
+	// the search fails after reaching this limit.
+	pathMaxNodes = 10000
+)
+
+// Struct for map grid cell.
+type cell struct {
+	x, y int
+}
+
+// Struct for A* search node.
+type pathNode struct {
+	cell  cell
+	cost  float64
+	score float64
+	index int
+}
+
+// Priority queue of search nodes, ordered by score.
+type pathQueue []*pathNode
+
+// Struct for grid of map cells, each cell is of the map
+// tile size.
+// Cell is passable if the visible layer on the cell center
+// is the ground layer.
+type passGrid struct {
+	width, height int
+	tileSize      pixel.Vec
+	cells         []bool
+}
+
+// Path returns waypoints of the shortest path between specified
+// positions that leads only through passable map tiles.
+// The last waypoint is always the destination position.
+// Returns nil if there is no such path.
+func (a *Area) Path(fromX, fromY, toX, toY float64) []game.Waypoint {
+	if a.grid == nil || a.grid.width < 1 || !a.PassablePosition(pixel.V(toX, toY)) {
+		return nil
+	}
+	start := a.grid.cell(fromX, fromY)
+	goal := a.grid.cell(toX, toY)
+	passable := func(c cell) bool {
+		return c == start || a.grid.passable(c)
+	}
+	cells := findPath(start, goal, a.grid.width, a.grid.height, passable, pathMaxNodes)
+	if cells == nil {
+		return nil
+	}
+	path := make([]game.Waypoint, 0)
+	for _, c := range simplifyPath(cells)[1:] {
+		if c == goal {
+			break
+		}
+		pos := a.grid.center(c)
+		path = append(path, game.Waypoint{X: pos.X, Y: pos.Y})
+	}
+	path = append(path, game.Waypoint{X: toX, Y: toY})
+	return path
+}
+
+// newPassGrid creates grid with passable cells of specified
+// map.
+// All map tiles are checked only once, so the grid should be
+// created once for the map.
+func newPassGrid(m *stone.Map) *passGrid {
+	g := passGrid{tileSize: m.TileSize()}
+	if g.tileSize.X <= 0 || g.tileSize.Y <= 0 {
+		return &g
+	}
+	g.width = int(math.Ceil(m.Size().X / g.tileSize.X))
+	g.height = int(math.Ceil(m.Size().Y / g.tileSize.Y))
+	g.cells = make([]bool, g.width*g.height)
+	// Top layers cover cells of the bottom layers.
+	for _, l := range m.Layers() {
+		ground := l.Name() == "ground"
+		for _, t := range l.Tiles() {
+			bounds := t.Bounds()
+			first := g.cell(bounds.Min.X, bounds.Min.Y)
+			last := g.cell(bounds.Max.X, bounds.Max.Y)
+			for y := first.y - 1; y <= last.y; y++ {
+				for x := first.x - 1; x <= last.x; x++ {
+					c := cell{x, y}
+					if g.inGrid(c) && bounds.Contains(g.center(c)) {
+						g.cells[y*g.width+x] = ground
+					}
+				}
+			}
+		}
+	}
+	return &g
+}
+
+// cell returns grid cell with specified position.
+func (g *passGrid) cell(x, y float64) cell {
+	return cell{int(x / g.tileSize.X), int(y / g.tileSize.Y)}
+}
+
+// center returns position of the center of specified cell.
+func (g *passGrid) center(c cell) pixel.Vec {
+	return pixel.V((float64(c.x)+0.5)*g.tileSize.X, (float64(c.y)+0.5)*g.tileSize.Y)
+}
+
+// inGrid checks if specified cell is inside the grid.
+func (g *passGrid) inGrid(c cell) bool {
+	return c.x >= 0 && c.y >= 0 && c.x < g.width && c.y < g.height
+}
+
+// passable checks if specified cell is passable.
+func (g *passGrid) passable(c cell) bool {
+	return g.inGrid(c) && g.cells[c.y*g.width+c.x]
+}
+
+// findPath finds the shortest path between specified cells of the grid
+// with specified size, with the A* algorithm.
+// Path can lead in 8 directions, but diagonal moves are allowed only if
+// both adjacent cells are passable.
+// Search visits at most specified max number of nodes.
+// Returned path starts with the start cell and ends with the goal cell,
+// nil is returned if there is no path between cells or the path was
+// not found within the nodes limit.
+func findPath(start, goal cell, width, height int, passable func(c cell) bool, maxNodes int) []cell {
+	inGrid := func(c cell) bool {
+		return c.x >= 0 && c.y >= 0 && c.x < width && c.y < height
+	}
+	if !inGrid(start) || !inGrid(goal) || !passable(goal) {
+		return nil
+	}
+	open := &pathQueue{}
+	heap.Push(open, &pathNode{cell: start, score: heuristic(start, goal)})
+	costs := map[cell]float64{start: 0}
+	parents := make(map[cell]cell)
+	closed := make(map[cell]bool)
+	for open.Len() > 0 && len(closed) < maxNodes {
+		node := heap.Pop(open).(*pathNode)
+		if node.cell == goal {
+			return tracePath(parents, start, goal)
+		}
+		if closed[node.cell] {
+			continue
+		}
+		closed[node.cell] = true
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				next := cell{node.cell.x + dx, node.cell.y + dy}
+				if !inGrid(next) || closed[next] || !passable(next) {
+					continue
+				}
+				stepCost := 1.0
+				if dx != 0 && dy != 0 {
+					// No corner cutting.
+					if !passable(cell{node.cell.x + dx, node.cell.y}) ||
+						!passable(cell{node.cell.x, node.cell.y + dy}) {
+						continue
+					}
+					stepCost = math.Sqrt2
+				}
+				cost := node.cost + stepCost
+				if c, ok := costs[next]; ok && c <= cost {
+					continue
+				}
+				costs[next] = cost
+				parents[next] = node.cell
+				heap.Push(open, &pathNode{
+					cell:  next,
+					cost:  cost,
+					score: cost + heuristic(next, goal),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// tracePath returns path from start to goal cell, recreated from
+// specified parent cells.
+func tracePath(parents map[cell]cell, start, goal cell) []cell {
+	path := []cell{goal}
+	for c := goal; c != start; {
+		c = parents[c]
+		path = append(path, c)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// simplifyPath removes cells from the middle of straight path
+// segments, only the start, goal and turn cells are left.
+func simplifyPath(path []cell) []cell {
+	if len(path) < 3 {
+		return path
+	}
+	simple := []cell{path[0]}
+	for i := 1; i < len(path)-1; i++ {
+		prev, next := path[i-1], path[i+1]
+		if path[i].x-prev.x == next.x-path[i].x &&
+			path[i].y-prev.y == next.y-path[i].y {
+			continue
+		}
+		simple = append(simple, path[i])
+	}
+	return append(simple, path[len(path)-1])
+}
+
+// heuristic returns octile distance between specified cells.
+func heuristic(from, to cell) float64 {
+	dx := math.Abs(float64(from.x - to.x))
+	dy := math.Abs(float64(from.y - to.y))
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+// Len returns number of nodes in queue.
+func (pq pathQueue) Len() int {
+	return len(pq)
+}
+
+// Less checks if node with index i has lower score then
+// node with index j.
+func (pq pathQueue) Less(i, j int) bool {
+	return pq[i].score < pq[j].score
+}
+
+// Swap swaps nodes with specified indexes.
+func (pq pathQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+// Push adds specified node to the queue.
+func (pq *pathQueue) Push(x any) {
+	node := x.(*pathNode)
+	node.index = len(*pq)
+	*pq = append(*pq, node)
+}
+
+// Pop removes and returns the last node from the queue.
+func (pq *pathQueue) Pop() any {
+	old := *pq
+	n := len(old)
+	node := old[n-1]
+	old[n-1] = nil
+	*pq = old[:n-1]
+	return node
+}
@@ -0,0 +1,107 @@
+/*
+ * path_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package object
+
+import (
+	"testing"
+)
+
+// testGrid returns passable function for test grid,
+// with '#' as impassable cells.
+func testGrid(grid []string) func(c cell) bool {
+	return func(c cell) bool {
+		return grid[c.y][c.x] != '#'
+	}
+}
+
+// TestFindPath tests finding path around obstacles.
+func TestFindPath(t *testing.T) {
+	grid := []string{
+		".....",
+		".###.",
+		"...#.",
+		"##.#.",
+		".....",
+	}
+	start, goal := cell{0, 0}, cell{2, 2}
+	path := findPath(start, goal, 5, 5, testGrid(grid), pathMaxNodes)
+	if len(path) < 1 {
+		t.Fatalf("No path found")
+	}
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Errorf("Invalid path ends: %v", path)
+	}
+	for _, c := range path {
+		if grid[c.y][c.x] == '#' {
+			t.Errorf("Path leads through obstacle: %v", c)
+		}
+	}
+	// Shortest path leads around the left side of the wall.
+	if len(path) != 5 {
+		t.Errorf("Path is not the shortest one: %v", path)
+	}
+}
+
+// TestFindPathNoCornerCutting tests if path doesn't lead
+// diagonally between two obstacles.
+func TestFindPathNoCornerCutting(t *testing.T) {
+	grid := []string{
+		".#",
+		"#.",
+	}
+	path := findPath(cell{0, 0}, cell{1, 1}, 2, 2, testGrid(grid), pathMaxNodes)
+	if path != nil {
+		t.Errorf("Path found through obstacle corner: %v", path)
+	}
+}
+
+// TestFindPathBlocked tests finding path to impassable cell.
+func TestFindPathBlocked(t *testing.T) {
+	grid := []string{
+		"..",
+		".#",
+	}
+	path := findPath(cell{0, 0}, cell{1, 1}, 2, 2, testGrid(grid), pathMaxNodes)
+	if path != nil {
+		t.Errorf("Path found to impassable cell: %v", path)
+	}
+}
+
+// TestFindPathLimit tests stopping the path search after
+// reaching the nodes limit.
+func TestFindPathLimit(t *testing.T) {
+	grid := []string{
+		"..........",
+		"..........",
+		"..........",
+	}
+	path := findPath(cell{0, 0}, cell{9, 2}, 10, 3, testGrid(grid), 5)
+	if path != nil {
+		t.Errorf("Path found after reaching nodes limit: %v", path)
+	}
+	path = findPath(cell{0, 0}, cell{9, 2}, 10, 3, testGrid(grid), 100)
+	if path == nil {
+		t.Errorf("No path found within nodes limit")
+	}
+}